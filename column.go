@@ -0,0 +1,112 @@
+package gorp
+
+import "reflect"
+
+// ColumnMap describes one mapped struct field's relationship to a table
+// column. Construction and mutation both go through TableMap (via
+// AddTableWithName and SetKeys); the zero value is not meaningful on its
+// own.
+type ColumnMap struct {
+	// ColumnName is the column this field maps to. It defaults to the
+	// field's name and can be overridden with a `db:"..."` struct tag.
+	ColumnName string
+
+	// MaxSize is the size passed to Dialect.ToSqlType when generating DDL
+	// for this column, e.g. the N in varchar(N). 0 means "use the
+	// dialect's default".
+	MaxSize int
+
+	// Unique, when set by a future table-definition call, requests a
+	// UNIQUE constraint on this column. Reserved for that use; nothing in
+	// this package sets it yet.
+	Unique bool
+
+	// Transient columns are mapped struct fields gorp ignores entirely:
+	// they're skipped by Insert/Update/Get/CreateTable, and Select does
+	// not scan into them even when a query returns a column of the same
+	// name. Set with a `db:"-"` struct tag.
+	Transient bool
+
+	fieldName  string
+	goType     reflect.Type
+	isPK       bool
+	isAutoIncr bool
+}
+
+// TableMap describes how a Go struct type maps onto a database table. Get
+// one by calling DbMap.AddTable or DbMap.AddTableWithName.
+type TableMap struct {
+	// TableName is the table this type maps to.
+	TableName string
+
+	// SchemaName qualifies TableName for dialects that support schemas.
+	// Empty means "use the connection's default schema".
+	SchemaName string
+
+	// Columns describes every mapped field, in struct field order.
+	Columns []*ColumnMap
+
+	gotype reflect.Type
+	keys   []*ColumnMap
+	dbmap  *DbMap
+}
+
+// SetKeys marks fieldNames as tableMap's primary key, in the order given,
+// and records whether the underlying column is autoincrement. Update,
+// Delete and Get require a table's keys to have been set; Insert does not.
+func (t *TableMap) SetKeys(isAutoIncr bool, fieldNames ...string) *TableMap {
+	t.keys = t.keys[:0]
+	for _, name := range fieldNames {
+		for _, col := range t.Columns {
+			if col.fieldName == name {
+				col.isPK = true
+				col.isAutoIncr = isAutoIncr
+				t.keys = append(t.keys, col)
+			}
+		}
+	}
+	return t
+}
+
+// ColMap returns the ColumnMap for fieldName, or nil if fieldName isn't
+// mapped.
+func (t *TableMap) ColMap(fieldName string) *ColumnMap {
+	for _, col := range t.Columns {
+		if col.fieldName == fieldName {
+			return col
+		}
+	}
+	return nil
+}
+
+// columnsFor reflects over t's exported fields, building one ColumnMap per
+// field. A `db:"name"` tag overrides the column name; `db:"-"` marks the
+// field Transient.
+func columnsFor(t reflect.Type) []*ColumnMap {
+	cols := make([]*ColumnMap, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			// unexported field
+			continue
+		}
+
+		name := f.Name
+		transient := false
+		if tag, ok := f.Tag.Lookup("db"); ok {
+			if tag == "-" {
+				transient = true
+			} else if tag != "" {
+				name = tag
+			}
+		}
+
+		cols = append(cols, &ColumnMap{
+			ColumnName: name,
+			Transient:  transient,
+			fieldName:  f.Name,
+			goType:     f.Type,
+		})
+	}
+	return cols
+}
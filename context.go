@@ -0,0 +1,245 @@
+package gorp
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SqlExecutorContext is the context-aware counterpart of SqlExecutor: the
+// Exec/Query/QueryRow trio plus the mapped CRUD operations, all taking a
+// context.Context that's threaded down to the driver call that finally
+// runs (including, for Insert/Update/Delete/Get, whichever hook methods
+// the argument implements — see HasPreInsertContext and friends below).
+// The exception is the insert statement for a row with an autoincrement
+// key on a dialect implementing IntegerAutoIncrInserter or
+// TargetedAutoIncrInserter: that statement runs through the dialect's
+// method, which calls the executor's contextless Exec or Query, so ctx
+// does not reach that driver call.
+// DbMap and Transaction both implement it; SqlExecutor's own methods are
+// thin wrappers that call these with context.Background().
+//
+// BeginTx isn't part of this interface: it's a *DbMap-only operation
+// (transactions don't nest), so it's declared directly on DbMap instead.
+type SqlExecutorContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+
+	GetContext(ctx context.Context, i interface{}, keys ...interface{}) (interface{}, error)
+	InsertContext(ctx context.Context, list ...interface{}) error
+	UpdateContext(ctx context.Context, list ...interface{}) (int64, error)
+	DeleteContext(ctx context.Context, list ...interface{}) (int64, error)
+	SelectContext(ctx context.Context, i interface{}, query string, args ...interface{}) ([]interface{}, error)
+}
+
+// HasPreInsertContext, HasPostInsertContext, HasPreUpdateContext,
+// HasPostUpdateContext, HasPreDeleteContext, HasPostDeleteContext and
+// HasPostGetContext are the context-aware counterparts of gorp.go's
+// HasPreInsert and friends. InsertContext/UpdateContext/DeleteContext/
+// GetContext prefer these when an argument implements both: a struct only
+// needs one or the other, not both, to hook into either the contextless
+// or the context-aware CRUD path.
+type (
+	HasPreInsertContext interface {
+		PreInsertContext(context.Context, SqlExecutor) error
+	}
+	HasPostInsertContext interface {
+		PostInsertContext(context.Context, SqlExecutor) error
+	}
+	HasPreUpdateContext interface {
+		PreUpdateContext(context.Context, SqlExecutor) error
+	}
+	HasPostUpdateContext interface {
+		PostUpdateContext(context.Context, SqlExecutor) error
+	}
+	HasPreDeleteContext interface {
+		PreDeleteContext(context.Context, SqlExecutor) error
+	}
+	HasPostDeleteContext interface {
+		PostDeleteContext(context.Context, SqlExecutor) error
+	}
+	HasPostGetContext interface {
+		PostGetContext(context.Context, SqlExecutor) error
+	}
+)
+
+func preInsert(ctx context.Context, ptr interface{}, exec SqlExecutor) error {
+	if hook, ok := ptr.(HasPreInsertContext); ok {
+		return hook.PreInsertContext(ctx, exec)
+	}
+	if hook, ok := ptr.(HasPreInsert); ok {
+		return hook.PreInsert(exec)
+	}
+	return nil
+}
+
+func postInsert(ctx context.Context, ptr interface{}, exec SqlExecutor) error {
+	if hook, ok := ptr.(HasPostInsertContext); ok {
+		return hook.PostInsertContext(ctx, exec)
+	}
+	if hook, ok := ptr.(HasPostInsert); ok {
+		return hook.PostInsert(exec)
+	}
+	return nil
+}
+
+func preUpdate(ctx context.Context, ptr interface{}, exec SqlExecutor) error {
+	if hook, ok := ptr.(HasPreUpdateContext); ok {
+		return hook.PreUpdateContext(ctx, exec)
+	}
+	if hook, ok := ptr.(HasPreUpdate); ok {
+		return hook.PreUpdate(exec)
+	}
+	return nil
+}
+
+func postUpdate(ctx context.Context, ptr interface{}, exec SqlExecutor) error {
+	if hook, ok := ptr.(HasPostUpdateContext); ok {
+		return hook.PostUpdateContext(ctx, exec)
+	}
+	if hook, ok := ptr.(HasPostUpdate); ok {
+		return hook.PostUpdate(exec)
+	}
+	return nil
+}
+
+func preDelete(ctx context.Context, ptr interface{}, exec SqlExecutor) error {
+	if hook, ok := ptr.(HasPreDeleteContext); ok {
+		return hook.PreDeleteContext(ctx, exec)
+	}
+	if hook, ok := ptr.(HasPreDelete); ok {
+		return hook.PreDelete(exec)
+	}
+	return nil
+}
+
+func postDelete(ctx context.Context, ptr interface{}, exec SqlExecutor) error {
+	if hook, ok := ptr.(HasPostDeleteContext); ok {
+		return hook.PostDeleteContext(ctx, exec)
+	}
+	if hook, ok := ptr.(HasPostDelete); ok {
+		return hook.PostDelete(exec)
+	}
+	return nil
+}
+
+func postGet(ctx context.Context, ptr interface{}, exec SqlExecutor) error {
+	if hook, ok := ptr.(HasPostGetContext); ok {
+		return hook.PostGetContext(ctx, exec)
+	}
+	if hook, ok := ptr.(HasPostGet); ok {
+		return hook.PostGet(exec)
+	}
+	return nil
+}
+
+// BeginTx starts a transaction with the given context and options. The
+// context is used until the transaction is committed or rolled back; if
+// ctx is cancelled, BeginTx's Transaction rolls back automatically.
+//
+// Begin is a thin wrapper calling BeginTx(context.Background(), nil).
+func (m *DbMap) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Transaction, error) {
+	tx, err := m.Db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{dbmap: m, tx: tx}, nil
+}
+
+func (m *DbMap) Begin() (*Transaction, error) {
+	return m.BeginTx(context.Background(), nil)
+}
+
+func (m *DbMap) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	m.trace(query, args...)
+	return m.Db.ExecContext(ctx, query, args...)
+}
+
+func (m *DbMap) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return m.ExecContext(context.Background(), query, args...)
+}
+
+func (m *DbMap) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	m.trace(query, args...)
+	return m.Db.QueryContext(ctx, query, args...)
+}
+
+func (m *DbMap) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return m.QueryContext(context.Background(), query, args...)
+}
+
+func (m *DbMap) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	m.trace(query, args...)
+	return m.Db.QueryRowContext(ctx, query, args...)
+}
+
+func (m *DbMap) QueryRow(query string, args ...interface{}) *sql.Row {
+	return m.QueryRowContext(context.Background(), query, args...)
+}
+
+func (t *Transaction) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	t.dbmap.trace(query, args...)
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+func (t *Transaction) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.ExecContext(context.Background(), query, args...)
+}
+
+func (t *Transaction) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	t.dbmap.trace(query, args...)
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+func (t *Transaction) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+func (t *Transaction) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	t.dbmap.trace(query, args...)
+	return t.tx.QueryRowContext(ctx, query, args...)
+}
+
+func (t *Transaction) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.QueryRowContext(context.Background(), query, args...)
+}
+
+func (m *DbMap) GetContext(ctx context.Context, i interface{}, keys ...interface{}) (interface{}, error) {
+	return get(ctx, m, m, i, keys...)
+}
+
+func (m *DbMap) InsertContext(ctx context.Context, list ...interface{}) error {
+	return insert(ctx, m, m, list...)
+}
+
+func (m *DbMap) UpdateContext(ctx context.Context, list ...interface{}) (int64, error) {
+	return update(ctx, m, m, list...)
+}
+
+func (m *DbMap) DeleteContext(ctx context.Context, list ...interface{}) (int64, error) {
+	return deleteRows(ctx, m, m, list...)
+}
+
+func (m *DbMap) SelectContext(ctx context.Context, i interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	return selectRows(ctx, m, i, query, args...)
+}
+
+func (t *Transaction) GetContext(ctx context.Context, i interface{}, keys ...interface{}) (interface{}, error) {
+	return get(ctx, t.dbmap, t, i, keys...)
+}
+
+func (t *Transaction) InsertContext(ctx context.Context, list ...interface{}) error {
+	return insert(ctx, t.dbmap, t, list...)
+}
+
+func (t *Transaction) UpdateContext(ctx context.Context, list ...interface{}) (int64, error) {
+	return update(ctx, t.dbmap, t, list...)
+}
+
+func (t *Transaction) DeleteContext(ctx context.Context, list ...interface{}) (int64, error) {
+	return deleteRows(ctx, t.dbmap, t, list...)
+}
+
+func (t *Transaction) SelectContext(ctx context.Context, i interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	return selectRows(ctx, t, i, query, args...)
+}
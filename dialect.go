@@ -0,0 +1,81 @@
+package gorp
+
+import (
+	"reflect"
+	"time"
+)
+
+// Dialect abstracts the SQL differences between database backends: type
+// mapping, bind variable syntax, identifier quoting, and the handful of
+// DDL/paging idioms that aren't portable SQL. Each supported backend has
+// its own file (dialect_mysql.go, dialect_postgres.go, dialect_sqlite.go,
+// dialect_mssql.go) implementing this interface as a value type, so a
+// Dialect is cheap to embed in a DbMap and safe to compare/type-switch on.
+type Dialect interface {
+	// QuerySuffix is appended to every statement gorp builds, e.g. ";" or
+	// "".
+	QuerySuffix() string
+
+	// ToSqlType returns the column type used to create a column holding
+	// Go values of type val. maxsize is the struct tag's requested size
+	// (0 meaning "use the dialect's default"); isAutoIncr is true for the
+	// table's autoincrement primary key, which some dialects type
+	// differently (e.g. Postgres's serial/bigserial).
+	ToSqlType(val reflect.Type, maxsize int, isAutoIncr bool) string
+
+	// AutoIncrStr is appended to an autoincrement column's type in a
+	// CREATE TABLE, e.g. MySQL's "auto_increment".
+	AutoIncrStr() string
+
+	// AutoIncrBindValue is the literal SQL gorp writes into an insert's
+	// VALUES list for the autoincrement column, e.g. Postgres's "DEFAULT".
+	// Dialects that omit the autoincrement column from the insert
+	// entirely instead (MySQL, SQL Server) return "". SQLite writes
+	// "null", which SQLite replaces with a generated value.
+	AutoIncrBindValue() string
+
+	// AutoIncrInsertSuffix is appended after an insert's VALUES clause for
+	// dialects that report the generated value via a trailing clause,
+	// e.g. Postgres's "RETURNING id".
+	AutoIncrInsertSuffix(col *ColumnMap) string
+
+	CreateTableSuffix() string
+	CreateIndexSuffix() string
+	DropIndexSuffix() string
+	TruncateClause() string
+	SleepClause(s time.Duration) string
+
+	// BindVar returns the placeholder for the i'th bind parameter (0
+	// indexed) in a statement, e.g. "?" or fmt.Sprintf("$%d", i+1).
+	BindVar(i int) string
+
+	QuoteField(field string) string
+	QuotedTableForQuery(schema string, table string) string
+
+	IfSchemaNotExists(command, schema string) string
+	IfTableExists(command, schema, table string) string
+	IfTableNotExists(command, schema, table string) string
+}
+
+// IntegerAutoIncrInserter is implemented by dialects (MySQL, SQLite) whose
+// driver reports a newly inserted row's autoincrement value via
+// sql.Result.LastInsertId.
+type IntegerAutoIncrInserter interface {
+	InsertAutoIncr(exec SqlExecutor, insertSql string, params ...interface{}) (int64, error)
+}
+
+// TargetedAutoIncrInserter is implemented by dialects (Postgres, SQL
+// Server) whose driver instead reports the generated value via a returned
+// row that must be scanned straight into target.
+type TargetedAutoIncrInserter interface {
+	InsertAutoIncrToTarget(exec SqlExecutor, insertSql string, target interface{}, params ...interface{}) error
+}
+
+// AutoIncrInfixInserter is implemented by dialects that need their
+// auto-increment reporting clause placed between an insert's column list
+// and its VALUES clause rather than after it (SQL Server's "OUTPUT
+// INSERTED.col", which AutoIncrInsertSuffix's trailing position can't
+// express).
+type AutoIncrInfixInserter interface {
+	AutoIncrInsertInfix(col *ColumnMap) string
+}
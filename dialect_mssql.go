@@ -0,0 +1,192 @@
+package gorp
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SqlServerDialect implements the Dialect interface for Microsoft SQL
+// Server, for use with the github.com/denisenkom/go-mssqldb driver.
+//
+//	dbmap := &gorp.DbMap{Db: db, Dialect: gorp.SqlServerDialect{}}
+type SqlServerDialect struct {
+	// UseOffsetFetch selects the SQL Server 2012+ "OFFSET ... FETCH NEXT"
+	// paging syntax in RewriteLimitOffset. When false (the default, so
+	// that gorp keeps working against SQL Server 2008 and earlier)
+	// paging is rewritten using a ROW_NUMBER OVER (...) / TOP query
+	// instead.
+	UseOffsetFetch bool
+
+	suffix string
+}
+
+func (d SqlServerDialect) QuerySuffix() string { return ";" }
+
+func (d SqlServerDialect) ToSqlType(val reflect.Type, maxsize int, isAutoIncr bool) string {
+	switch val.Kind() {
+	case reflect.Ptr:
+		return d.ToSqlType(val.Elem(), maxsize, isAutoIncr)
+	case reflect.Bool:
+		return "bit"
+	case reflect.Int8, reflect.Uint8:
+		return "tinyint"
+	case reflect.Int16, reflect.Uint16:
+		return "smallint"
+	case reflect.Int, reflect.Int32, reflect.Uint, reflect.Uint32:
+		return "int"
+	case reflect.Int64, reflect.Uint64:
+		return "bigint"
+	case reflect.Float64:
+		return "float(53)"
+	case reflect.Float32:
+		return "real"
+	case reflect.Slice:
+		if val.Elem().Kind() == reflect.Uint8 {
+			if maxsize < 1 {
+				return "varbinary(max)"
+			}
+			return fmt.Sprintf("varbinary(%d)", maxsize)
+		}
+	}
+
+	switch val.Name() {
+	case "NullBool":
+		return "bit"
+	case "NullInt64":
+		return "bigint"
+	case "NullFloat64":
+		return "float(53)"
+	case "Time":
+		return "datetime2"
+	}
+
+	if maxsize < 1 {
+		maxsize = 255
+	}
+	return fmt.Sprintf("varchar(%d)", maxsize)
+}
+
+// AutoIncrStr is appended to the column's type when it is declared, e.g.
+// "int IDENTITY(1,1)".
+func (d SqlServerDialect) AutoIncrStr() string {
+	return "IDENTITY(1,1)"
+}
+
+// AutoIncrBindValue is unused on SQL Server: identity columns are left out
+// of the insert's column/value lists entirely, as with MySQL and SQLite.
+func (d SqlServerDialect) AutoIncrBindValue() string {
+	return ""
+}
+
+func (d SqlServerDialect) AutoIncrInsertSuffix(col *ColumnMap) string {
+	return ""
+}
+
+// AutoIncrInsertInfix returns the "OUTPUT INSERTED.<col>" clause gorp
+// writes between an insert's column list and its VALUES clause, which is
+// where SQL Server requires OUTPUT to appear (AutoIncrInsertSuffix, which
+// other dialects use for a trailing RETURNING-style clause, isn't
+// positioned correctly for SQL Server's syntax).
+func (d SqlServerDialect) AutoIncrInsertInfix(col *ColumnMap) string {
+	return " OUTPUT INSERTED." + d.QuoteField(col.ColumnName)
+}
+
+func (d SqlServerDialect) CreateTableSuffix() string { return "" }
+
+func (d SqlServerDialect) CreateIndexSuffix() string { return "" }
+
+func (d SqlServerDialect) DropIndexSuffix() string { return "" }
+
+func (d SqlServerDialect) TruncateClause() string { return "truncate table" }
+
+func (d SqlServerDialect) SleepClause(s time.Duration) string {
+	return fmt.Sprintf("WAITFOR DELAY '%s'", time.Unix(0, 0).UTC().Add(s).Format("15:04:05"))
+}
+
+// BindVar returns go-mssqldb's positional parameter style, "@p1", "@p2", ...
+func (d SqlServerDialect) BindVar(i int) string {
+	return fmt.Sprintf("@p%d", i+1)
+}
+
+func (d SqlServerDialect) QuoteField(f string) string {
+	return "[" + strings.Replace(f, "]", "]]", -1) + "]"
+}
+
+func (d SqlServerDialect) QuotedTableForQuery(schema string, table string) string {
+	if strings.TrimSpace(schema) == "" {
+		return d.QuoteField(table)
+	}
+	return d.QuoteField(schema) + "." + d.QuoteField(table)
+}
+
+func (d SqlServerDialect) IfSchemaNotExists(command, schema string) string {
+	return fmt.Sprintf("if not exists (select 1 from sys.schemas where name = '%s') %s", schema, command)
+}
+
+func (d SqlServerDialect) IfTableExists(command, schema, table string) string {
+	return fmt.Sprintf("if exists (select 1 from sys.tables where object_id = object_id('%s')) %s", d.QuotedTableForQuery(schema, table), command)
+}
+
+func (d SqlServerDialect) IfTableNotExists(command, schema, table string) string {
+	return fmt.Sprintf("if not exists (select 1 from sys.tables where object_id = object_id('%s')) %s", d.QuotedTableForQuery(schema, table), command)
+}
+
+// InsertAutoIncrToTarget runs an insert that reports its generated identity
+// value via "OUTPUT INSERTED.<col>" rather than last_insert_id(), scanning
+// the single returned row into target. insertSql must already contain the
+// OUTPUT clause; see AutoIncrInsertInfix.
+func (d SqlServerDialect) InsertAutoIncrToTarget(exec SqlExecutor, insertSql string, target interface{}, params ...interface{}) error {
+	rows, err := exec.Query(insertSql, params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("gorp: no OUTPUT row returned for insert: %s", insertSql)
+	}
+	if err := rows.Scan(target); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+var mssqlOrderByRe = regexp.MustCompile(`(?is)\s+order\s+by\s+`)
+
+// RewriteLimitOffset rewrites query, which must not already contain a
+// LIMIT/OFFSET clause of its own, into the SQL Server equivalent of
+// "<query> LIMIT limit OFFSET offset". query must end in an ORDER BY
+// clause, since both SQL Server paging strategies require one.
+//
+// When UseOffsetFetch is true this appends the SQL Server 2012+
+// "OFFSET ... FETCH NEXT ... ROWS ONLY" syntax. Otherwise it wraps query in
+// a ROW_NUMBER() OVER (...) subquery and filters on that with TOP, which
+// works against SQL Server 2008 and earlier.
+func (d SqlServerDialect) RewriteLimitOffset(query string, limit, offset int) string {
+	if d.UseOffsetFetch {
+		return fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", query, offset, limit)
+	}
+
+	locs := mssqlOrderByRe.FindAllStringIndex(query, -1)
+	if locs == nil {
+		// No ORDER BY to hoist into the window function; caller's query is
+		// malformed for paging purposes, so leave it untouched.
+		return query
+	}
+	// Hoist the last ORDER BY, not the first: an earlier one may belong to
+	// a window function, a derived-table subquery, or a UNION branch
+	// rather than being the trailing clause the caller means to page on.
+	loc := locs[len(locs)-1]
+	body, orderBy := query[:loc[0]], strings.TrimSpace(query[loc[0]:])
+
+	return fmt.Sprintf(
+		"SELECT TOP (%d) * FROM (SELECT gorp_paged.*, ROW_NUMBER() OVER (%s) AS gorp_row_num FROM (%s) AS gorp_paged) AS gorp_windowed WHERE gorp_row_num > %d",
+		limit, orderBy, body, offset,
+	)
+}
@@ -0,0 +1,68 @@
+package gorp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSqlServerDialect_RewriteLimitOffset_OffsetFetch(t *testing.T) {
+	d := SqlServerDialect{UseOffsetFetch: true}
+	got := d.RewriteLimitOffset("select id from foo order by id", 10, 20)
+	want := "select id from foo order by id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestSqlServerDialect_RewriteLimitOffset_Top(t *testing.T) {
+	d := SqlServerDialect{}
+	got := d.RewriteLimitOffset("select id, name from foo order by name", 10, 20)
+
+	if !strings.Contains(got, "ROW_NUMBER() OVER (order by name)") {
+		t.Fatalf("expected the ORDER BY to be hoisted into ROW_NUMBER's OVER clause, got %q", got)
+	}
+	if !strings.Contains(got, "SELECT TOP (10)") {
+		t.Fatalf("expected the limit to become TOP (10), got %q", got)
+	}
+	if !strings.Contains(got, "gorp_row_num > 20") {
+		t.Fatalf("expected the offset to become a gorp_row_num > 20 filter, got %q", got)
+	}
+	if !strings.Contains(got, "FROM (select id, name from foo) AS gorp_paged") {
+		t.Fatalf("expected the query body without its ORDER BY to be wrapped, got %q", got)
+	}
+}
+
+func TestSqlServerDialect_RewriteLimitOffset_UsesLastOrderBy(t *testing.T) {
+	// The subquery's own ORDER BY must not be mistaken for the trailing
+	// one the caller means to page on.
+	d := SqlServerDialect{}
+	query := "select * from (select id from foo order by created_at) t order by id"
+	got := d.RewriteLimitOffset(query, 5, 0)
+
+	if !strings.Contains(got, "ROW_NUMBER() OVER (order by id)") {
+		t.Fatalf("expected the final ORDER BY (order by id) to be hoisted, got %q", got)
+	}
+	if !strings.Contains(got, "FROM (select * from (select id from foo order by created_at) t) AS gorp_paged") {
+		t.Fatalf("expected the inner ORDER BY to stay inside the wrapped body, got %q", got)
+	}
+}
+
+func TestSqlServerDialect_QuoteField(t *testing.T) {
+	d := SqlServerDialect{}
+	if got, want := d.QuoteField("foo"), "[foo]"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := d.QuoteField("f]oo"), "[f]]oo]"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestSqlServerDialect_BindVar(t *testing.T) {
+	d := SqlServerDialect{}
+	if got, want := d.BindVar(0), "@p1"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := d.BindVar(2), "@p3"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package gorp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// MySQLDialect implements the Dialect interface for MySQL/MariaDB, for use
+// with the github.com/go-sql-driver/mysql driver.
+type MySQLDialect struct {
+	// Engine is the storage engine used in CreateTableSuffix, e.g.
+	// "InnoDB" (the default if left blank).
+	Engine string
+
+	// Encoding is the character set used in CreateTableSuffix, e.g.
+	// "UTF8" (the default if left blank).
+	Encoding string
+}
+
+func (d MySQLDialect) QuerySuffix() string { return ";" }
+
+func (d MySQLDialect) ToSqlType(val reflect.Type, maxsize int, isAutoIncr bool) string {
+	switch val.Kind() {
+	case reflect.Ptr:
+		return d.ToSqlType(val.Elem(), maxsize, isAutoIncr)
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8, reflect.Uint8:
+		return "tinyint"
+	case reflect.Int16, reflect.Uint16:
+		return "smallint"
+	case reflect.Int, reflect.Int32, reflect.Uint, reflect.Uint32:
+		return "int"
+	case reflect.Int64, reflect.Uint64:
+		return "bigint"
+	case reflect.Float64, reflect.Float32:
+		return "double"
+	case reflect.Slice:
+		if val.Elem().Kind() == reflect.Uint8 {
+			if maxsize < 1 {
+				return "longblob"
+			}
+			return fmt.Sprintf("varbinary(%d)", maxsize)
+		}
+	}
+
+	switch val.Name() {
+	case "NullBool":
+		return "boolean"
+	case "NullInt64":
+		return "bigint"
+	case "NullFloat64":
+		return "double"
+	case "Time":
+		return "datetime"
+	}
+
+	if maxsize < 1 {
+		maxsize = 255
+	}
+	return fmt.Sprintf("varchar(%d)", maxsize)
+}
+
+func (d MySQLDialect) AutoIncrStr() string { return "auto_increment" }
+
+// AutoIncrBindValue is unused on MySQL: the autoincrement column is left
+// out of the insert's column/value lists entirely, and its value is
+// reported back via LastInsertId (see InsertAutoIncr).
+func (d MySQLDialect) AutoIncrBindValue() string { return "" }
+
+func (d MySQLDialect) AutoIncrInsertSuffix(col *ColumnMap) string { return "" }
+
+func (d MySQLDialect) CreateTableSuffix() string {
+	engine := d.Engine
+	if engine == "" {
+		engine = "InnoDB"
+	}
+	encoding := d.Encoding
+	if encoding == "" {
+		encoding = "UTF8"
+	}
+	return fmt.Sprintf(" engine=%s charset=%s", engine, encoding)
+}
+
+func (d MySQLDialect) CreateIndexSuffix() string { return "using" }
+func (d MySQLDialect) DropIndexSuffix() string   { return "" }
+func (d MySQLDialect) TruncateClause() string    { return "truncate" }
+
+func (d MySQLDialect) SleepClause(s time.Duration) string {
+	return fmt.Sprintf("sleep(%f)", s.Seconds())
+}
+
+// BindVar is "?" on MySQL.
+func (d MySQLDialect) BindVar(i int) string { return "?" }
+
+func (d MySQLDialect) QuoteField(f string) string {
+	return "`" + strings.Replace(f, "`", "``", -1) + "`"
+}
+
+func (d MySQLDialect) QuotedTableForQuery(schema string, table string) string {
+	if strings.TrimSpace(schema) == "" {
+		return d.QuoteField(table)
+	}
+	return d.QuoteField(schema) + "." + d.QuoteField(table)
+}
+
+func (d MySQLDialect) IfSchemaNotExists(command, schema string) string {
+	return fmt.Sprintf("%s if not exists", command)
+}
+
+func (d MySQLDialect) IfTableExists(command, schema, table string) string {
+	return fmt.Sprintf("%s if exists", command)
+}
+
+func (d MySQLDialect) IfTableNotExists(command, schema, table string) string {
+	return fmt.Sprintf("%s if not exists", command)
+}
+
+// InsertAutoIncr runs insertSql and returns the generated autoincrement
+// value via sql.Result.LastInsertId.
+func (d MySQLDialect) InsertAutoIncr(exec SqlExecutor, insertSql string, params ...interface{}) (int64, error) {
+	res, err := exec.Exec(insertSql, params...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
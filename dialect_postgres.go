@@ -0,0 +1,142 @@
+package gorp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// PostgresDialect implements the Dialect interface for PostgreSQL, for use
+// with the github.com/jackc/pgx driver (via its database/sql shim).
+type PostgresDialect struct {
+	// LowercaseFields, when true, lowercases identifiers before quoting
+	// them, matching Postgres's own default folding behavior for
+	// unquoted identifiers.
+	LowercaseFields bool
+}
+
+func (d PostgresDialect) QuerySuffix() string { return ";" }
+
+func (d PostgresDialect) ToSqlType(val reflect.Type, maxsize int, isAutoIncr bool) string {
+	switch val.Kind() {
+	case reflect.Ptr:
+		return d.ToSqlType(val.Elem(), maxsize, isAutoIncr)
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16:
+		if isAutoIncr {
+			return "smallserial"
+		}
+		return "smallint"
+	case reflect.Int, reflect.Uint, reflect.Int32, reflect.Uint32:
+		if isAutoIncr {
+			return "serial"
+		}
+		return "integer"
+	case reflect.Int64, reflect.Uint64:
+		if isAutoIncr {
+			return "bigserial"
+		}
+		return "bigint"
+	case reflect.Float64, reflect.Float32:
+		return "double precision"
+	case reflect.Slice:
+		if val.Elem().Kind() == reflect.Uint8 {
+			return "bytea"
+		}
+	}
+
+	switch val.Name() {
+	case "NullBool":
+		return "boolean"
+	case "NullInt64":
+		return "bigint"
+	case "NullFloat64":
+		return "double precision"
+	case "Time":
+		return "timestamp with time zone"
+	}
+
+	if maxsize < 1 {
+		return "text"
+	}
+	return fmt.Sprintf("varchar(%d)", maxsize)
+}
+
+// AutoIncrStr is unused on Postgres: serial/bigserial columns carry their
+// own implicit sequence default, set via ToSqlType instead of a separate
+// clause.
+func (d PostgresDialect) AutoIncrStr() string { return "" }
+
+// AutoIncrBindValue lets the column's sequence default assign the value:
+// the column is included in the insert with the literal "DEFAULT" rather
+// than a bind parameter, so RETURNING can report what it picked.
+func (d PostgresDialect) AutoIncrBindValue() string { return "DEFAULT" }
+
+func (d PostgresDialect) AutoIncrInsertSuffix(col *ColumnMap) string {
+	return " returning " + d.QuoteField(col.ColumnName)
+}
+
+func (d PostgresDialect) CreateTableSuffix() string { return "" }
+func (d PostgresDialect) CreateIndexSuffix() string { return "using" }
+func (d PostgresDialect) DropIndexSuffix() string   { return "" }
+func (d PostgresDialect) TruncateClause() string    { return "truncate" }
+
+func (d PostgresDialect) SleepClause(s time.Duration) string {
+	return fmt.Sprintf("pg_sleep(%f)", s.Seconds())
+}
+
+// BindVar returns Postgres's positional parameter style, "$1", "$2", ...
+func (d PostgresDialect) BindVar(i int) string {
+	return fmt.Sprintf("$%d", i+1)
+}
+
+func (d PostgresDialect) QuoteField(f string) string {
+	if d.LowercaseFields {
+		f = strings.ToLower(f)
+	}
+	return `"` + strings.Replace(f, `"`, `""`, -1) + `"`
+}
+
+func (d PostgresDialect) QuotedTableForQuery(schema string, table string) string {
+	if strings.TrimSpace(schema) == "" {
+		return d.QuoteField(table)
+	}
+	return schema + "." + d.QuoteField(table)
+}
+
+func (d PostgresDialect) IfSchemaNotExists(command, schema string) string {
+	return fmt.Sprintf("%s if not exists", command)
+}
+
+func (d PostgresDialect) IfTableExists(command, schema, table string) string {
+	return fmt.Sprintf("%s if exists", command)
+}
+
+func (d PostgresDialect) IfTableNotExists(command, schema, table string) string {
+	return fmt.Sprintf("%s if not exists", command)
+}
+
+// InsertAutoIncrToTarget runs an insert whose RETURNING clause reports the
+// generated value, scanning the single returned row into target.
+// insertSql must already contain the RETURNING clause; see
+// AutoIncrInsertSuffix.
+func (d PostgresDialect) InsertAutoIncrToTarget(exec SqlExecutor, insertSql string, target interface{}, params ...interface{}) error {
+	rows, err := exec.Query(insertSql, params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("gorp: no RETURNING row for insert: %s", insertSql)
+	}
+	if err := rows.Scan(target); err != nil {
+		return err
+	}
+	return rows.Err()
+}
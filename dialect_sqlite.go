@@ -0,0 +1,200 @@
+package gorp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// sqlite3Driver and moderncDriver are the database/sql driver names
+// registered by github.com/mattn/go-sqlite3 and modernc.org/sqlite
+// respectively.
+const (
+	sqlite3Driver = "sqlite3"
+	moderncDriver = "sqlite"
+)
+
+// sqliteConstraintCode is SQLite's SQLITE_CONSTRAINT primary result code;
+// extended codes (e.g. SQLITE_CONSTRAINT_UNIQUE) carry it in their low
+// byte.
+const sqliteConstraintCode = 19
+
+// SqliteDialect implements the Dialect interface for SQLite. It works with
+// either github.com/mattn/go-sqlite3 (cgo) or modernc.org/sqlite (pure
+// Go, no cgo); set DriverName to tell it which one is in use.
+type SqliteDialect struct {
+	suffix string
+
+	// DriverName is the name the driver was registered under with
+	// database/sql: "sqlite3" for github.com/mattn/go-sqlite3, or
+	// "sqlite" for modernc.org/sqlite. It defaults to "sqlite3", so
+	// existing callers using mattn/go-sqlite3 are unaffected by leaving
+	// it unset. No method currently reads it: everything in this file
+	// emits plain SQL that both drivers accept identically, and
+	// IsConstraintError recognizes either driver's error type by itself.
+	DriverName string
+}
+
+func (d SqliteDialect) driverName() string {
+	if d.DriverName == "" {
+		return sqlite3Driver
+	}
+	return d.DriverName
+}
+
+func (d SqliteDialect) QuerySuffix() string { return ";" }
+
+func (d SqliteDialect) ToSqlType(val reflect.Type, maxsize int, isAutoIncr bool) string {
+	switch val.Kind() {
+	case reflect.Ptr:
+		return d.ToSqlType(val.Elem(), maxsize, isAutoIncr)
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16,
+		reflect.Int, reflect.Uint, reflect.Int32, reflect.Uint32,
+		reflect.Int64, reflect.Uint64:
+		return "integer"
+	case reflect.Float64, reflect.Float32:
+		return "real"
+	case reflect.Slice:
+		if val.Elem().Kind() == reflect.Uint8 {
+			return "blob"
+		}
+	}
+
+	switch val.Name() {
+	case "NullBool":
+		return "boolean"
+	case "NullInt64":
+		return "integer"
+	case "NullFloat64":
+		return "real"
+	case "Time":
+		return "datetime"
+	}
+
+	if maxsize < 1 {
+		maxsize = 255
+	}
+	return fmt.Sprintf("varchar(%d)", maxsize)
+}
+
+func (d SqliteDialect) AutoIncrStr() string                        { return "AUTOINCREMENT" }
+func (d SqliteDialect) AutoIncrBindValue() string                  { return "null" }
+func (d SqliteDialect) AutoIncrInsertSuffix(col *ColumnMap) string { return "" }
+
+func (d SqliteDialect) CreateTableSuffix() string { return d.suffix }
+func (d SqliteDialect) CreateIndexSuffix() string { return "" }
+func (d SqliteDialect) DropIndexSuffix() string   { return "" }
+func (d SqliteDialect) TruncateClause() string    { return "delete from" }
+
+func (d SqliteDialect) SleepClause(s time.Duration) string {
+	return fmt.Sprintf("select sleep(%f)", s.Seconds())
+}
+
+// BindVar is "?" for both drivers.
+func (d SqliteDialect) BindVar(i int) string { return "?" }
+
+func (d SqliteDialect) QuoteField(f string) string {
+	return `"` + strings.Replace(f, `"`, `""`, -1) + `"`
+}
+
+func (d SqliteDialect) QuotedTableForQuery(schema string, table string) string {
+	if strings.TrimSpace(schema) == "" {
+		return d.QuoteField(table)
+	}
+	return schema + "." + d.QuoteField(table)
+}
+
+func (d SqliteDialect) IfSchemaNotExists(command, schema string) string {
+	return fmt.Sprintf("%s if not exists", command)
+}
+
+func (d SqliteDialect) IfTableExists(command, schema, table string) string {
+	return fmt.Sprintf("%s if exists", command)
+}
+
+func (d SqliteDialect) IfTableNotExists(command, schema, table string) string {
+	return fmt.Sprintf("%s if not exists", command)
+}
+
+// InsertAutoIncr runs insertSql and returns the generated rowid via
+// sql.Result.LastInsertId.
+func (d SqliteDialect) InsertAutoIncr(exec SqlExecutor, insertSql string, params ...interface{}) (int64, error) {
+	res, err := exec.Exec(insertSql, params...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// Bootstrap runs the PRAGMA statements a new connection needs.
+// modernc.org/sqlite, unlike mattn/go-sqlite3, does not turn on foreign
+// key enforcement via a DSN query parameter, so callers using it should
+// run this once per connection (e.g. from a database/sql ConnectionHook,
+// or immediately after sql.Open) rather than relying on "?_foreign_keys=on"
+// in the DSN.
+func (d SqliteDialect) Bootstrap(exec SqlExecutor) error {
+	_, err := exec.Exec("PRAGMA foreign_keys = ON")
+	return err
+}
+
+// IsConstraintError reports whether err represents a SQLite constraint
+// violation (UNIQUE, NOT NULL, FOREIGN KEY, CHECK, ...). mattn/go-sqlite3
+// and modernc.org/sqlite each return their own concrete error type with
+// differently-shaped access to the underlying SQLite result code (a
+// "Code" field vs. a Code() method), so this reads it via reflection
+// instead of importing either driver package from this build-tag-free
+// file.
+func (d SqliteDialect) IsConstraintError(err error) bool {
+	if err == nil {
+		return false
+	}
+	code, ok := sqliteResultCode(err)
+	if !ok {
+		return false
+	}
+	return code&0xff == sqliteConstraintCode
+}
+
+func sqliteResultCode(err error) (int, bool) {
+	rv := reflect.ValueOf(err)
+
+	if m := rv.MethodByName("Code"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+		if out := m.Call(nil); len(out) == 1 {
+			if code, ok := asInt(out[0]); ok {
+				return code, true
+			}
+		}
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return 0, false
+	}
+	if code, ok := asInt(rv.FieldByName("Code")); ok {
+		return code, true
+	}
+	return 0, false
+}
+
+// asInt reads v as an int if its Kind is one of the sized integer kinds.
+// reflect.Value.CanInt/Int's "any integer kind" convenience wasn't added
+// until Go 1.18 (this module still targets go 1.14), so the Kind switch is
+// done by hand instead.
+func asInt(v reflect.Value) (int, bool) {
+	if !v.IsValid() {
+		return 0, false
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), true
+	default:
+		return 0, false
+	}
+}
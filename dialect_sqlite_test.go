@@ -0,0 +1,65 @@
+package gorp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqliteDialect_ToSqlType(t *testing.T) {
+	d := SqliteDialect{}
+	cases := []struct {
+		val  interface{}
+		want string
+	}{
+		{true, "boolean"},
+		{int8(0), "integer"},
+		{int64(0), "integer"},
+		{float64(0), "real"},
+		{[]byte(nil), "blob"},
+		{"", "varchar(255)"},
+	}
+	for _, c := range cases {
+		got := d.ToSqlType(reflect.TypeOf(c.val), 0, false)
+		if got != c.want {
+			t.Errorf("ToSqlType(%T) = %q, want %q", c.val, got, c.want)
+		}
+	}
+}
+
+func TestSqliteDialect_ToSqlType_Pointer(t *testing.T) {
+	d := SqliteDialect{}
+	var p *int64
+	if got, want := d.ToSqlType(reflect.TypeOf(p), 0, false), "integer"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// moderncCodeErr mimics modernc.org/sqlite's error type: a Code() int
+// method, no exported "Code" field.
+type moderncCodeErr struct{ code int }
+
+func (e moderncCodeErr) Error() string { return "constraint failed" }
+func (e moderncCodeErr) Code() int     { return e.code }
+
+// mattnCodeErr mimics github.com/mattn/go-sqlite3's error type: an
+// exported "Code" field, no Code() method.
+type mattnCodeErr struct{ Code int }
+
+func (e mattnCodeErr) Error() string { return "constraint failed" }
+
+func TestSqliteDialect_IsConstraintError(t *testing.T) {
+	d := SqliteDialect{}
+
+	if !d.IsConstraintError(moderncCodeErr{code: sqliteConstraintCode}) {
+		t.Error("expected modernc.org/sqlite-shaped error with code 19 to be a constraint error")
+	}
+	if !d.IsConstraintError(mattnCodeErr{Code: 19<<8 | sqliteConstraintCode}) {
+		t.Error("expected mattn/go-sqlite3-shaped extended code to be detected via its low byte")
+	}
+	if d.IsConstraintError(moderncCodeErr{code: 1}) {
+		t.Error("SQLITE_ERROR (1) should not be reported as a constraint error")
+	}
+	if d.IsConstraintError(nil) {
+		t.Error("nil error should not be reported as a constraint error")
+	}
+}
@@ -0,0 +1,510 @@
+// Package gorp provides a thin ORM-ish layer on top of database/sql:
+// struct-to-table mapping driven by `db:"..."` tags, and dialect-aware SQL
+// generation for the handful of things that aren't portable (bind
+// variables, identifier quoting, autoincrement reporting).
+package gorp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ctxExecutor is what insert/update/deleteRows/get/selectRows actually
+// need: a SqlExecutor that can also run its raw statements against a
+// specific context. DbMap and Transaction both satisfy it (see
+// context.go); it stays unexported because callers only ever reach these
+// through the *Context methods on those two types.
+type ctxExecutor interface {
+	SqlExecutor
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SqlExecutor is the common interface implemented by both *DbMap and
+// *Transaction: the mapped CRUD operations (Get/Insert/Update/Delete/
+// Select) plus the three raw database/sql passthroughs. Code that should
+// work whether or not it's running inside a transaction takes a
+// SqlExecutor rather than a concrete *DbMap.
+type SqlExecutor interface {
+	Get(i interface{}, keys ...interface{}) (interface{}, error)
+	Insert(list ...interface{}) error
+	Update(list ...interface{}) (int64, error)
+	Delete(list ...interface{}) (int64, error)
+	Select(i interface{}, query string, args ...interface{}) ([]interface{}, error)
+
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Logger is the subset of the standard library's log.Logger that DbMap
+// uses to trace the SQL it runs. Set DbMap.Logger to enable it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// DbMap wraps a *sql.DB with a Dialect and a registry of mapped tables
+// (via AddTable/AddTableWithName). It is the entry point for every mapped
+// operation; create one per database connection pool and share it.
+type DbMap struct {
+	// Db is the underlying connection pool. DbMap never closes it.
+	Db *sql.DB
+
+	// Dialect selects the SQL dialect DbMap generates.
+	Dialect Dialect
+
+	// Logger, if set, receives every statement DbMap or a Transaction
+	// derived from it runs.
+	Logger Logger
+
+	tables []*TableMap
+}
+
+// Transaction is a *DbMap-scoped wrapper around a *sql.Tx: it supports the
+// same mapped operations as DbMap, running them against the transaction
+// instead of the pool.
+type Transaction struct {
+	dbmap *DbMap
+	tx    *sql.Tx
+}
+
+func (m *DbMap) trace(query string, args ...interface{}) {
+	if m.Logger == nil {
+		return
+	}
+	m.Logger.Printf("%s %v", query, args)
+}
+
+// AddTable registers i's type (which must be a struct) as mapped to a
+// table named after the type, lowercased. It returns the TableMap so
+// callers can chain SetKeys.
+func (m *DbMap) AddTable(i interface{}) *TableMap {
+	return m.AddTableWithName(i, "")
+}
+
+// AddTableWithName registers i's type as mapped to a table named name. If
+// name is "", the type's name lowercased is used instead.
+func (m *DbMap) AddTableWithName(i interface{}, name string) *TableMap {
+	t := reflect.TypeOf(i)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if name == "" {
+		name = strings.ToLower(t.Name())
+	}
+
+	tmap := &TableMap{TableName: name, gotype: t, dbmap: m, Columns: columnsFor(t)}
+	m.tables = append(m.tables, tmap)
+	return tmap
+}
+
+// tableFor returns the TableMap registered for t. If checkPK is true, it
+// also requires that SetKeys was called for that table.
+func (m *DbMap) tableFor(t reflect.Type, checkPK bool) (*TableMap, error) {
+	for _, table := range m.tables {
+		if table.gotype != t {
+			continue
+		}
+		if checkPK && len(table.keys) == 0 {
+			return nil, fmt.Errorf("gorp: no keys defined for table %s (call SetKeys after AddTable)", table.TableName)
+		}
+		return table, nil
+	}
+	return nil, fmt.Errorf("gorp: type %s has not been registered with AddTable", t)
+}
+
+// HasPreInsert, HasPostInsert, HasPreUpdate, HasPostUpdate, HasPreDelete,
+// HasPostDelete and HasPostGet let a mapped struct hook into its own
+// CRUD lifecycle: if Insert's argument implements HasPreInsert, its
+// PreInsert is called (with the SqlExecutor driving the operation, so the
+// hook can run its own queries against the same connection/transaction)
+// before the row is written, and so on for the other hooks.
+type (
+	HasPreInsert  interface{ PreInsert(SqlExecutor) error }
+	HasPostInsert interface{ PostInsert(SqlExecutor) error }
+	HasPreUpdate  interface{ PreUpdate(SqlExecutor) error }
+	HasPostUpdate interface{ PostUpdate(SqlExecutor) error }
+	HasPreDelete  interface{ PreDelete(SqlExecutor) error }
+	HasPostDelete interface{ PostDelete(SqlExecutor) error }
+	HasPostGet    interface{ PostGet(SqlExecutor) error }
+)
+
+func (m *DbMap) Insert(list ...interface{}) error {
+	return m.InsertContext(context.Background(), list...)
+}
+func (m *DbMap) Update(list ...interface{}) (int64, error) {
+	return m.UpdateContext(context.Background(), list...)
+}
+func (m *DbMap) Delete(list ...interface{}) (int64, error) {
+	return m.DeleteContext(context.Background(), list...)
+}
+func (m *DbMap) Get(i interface{}, keys ...interface{}) (interface{}, error) {
+	return m.GetContext(context.Background(), i, keys...)
+}
+func (m *DbMap) Select(i interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	return m.SelectContext(context.Background(), i, query, args...)
+}
+
+func (t *Transaction) Insert(list ...interface{}) error {
+	return t.InsertContext(context.Background(), list...)
+}
+func (t *Transaction) Update(list ...interface{}) (int64, error) {
+	return t.UpdateContext(context.Background(), list...)
+}
+func (t *Transaction) Delete(list ...interface{}) (int64, error) {
+	return t.DeleteContext(context.Background(), list...)
+}
+func (t *Transaction) Get(i interface{}, keys ...interface{}) (interface{}, error) {
+	return t.GetContext(context.Background(), i, keys...)
+}
+func (t *Transaction) Select(i interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	return t.SelectContext(context.Background(), i, query, args...)
+}
+
+// Commit commits the underlying transaction.
+func (t *Transaction) Commit() error { return t.tx.Commit() }
+
+// Rollback rolls back the underlying transaction.
+func (t *Transaction) Rollback() error { return t.tx.Rollback() }
+
+// CreateTablesIfNotExists runs a CREATE TABLE IF NOT EXISTS (or the
+// dialect's equivalent) for every table registered with AddTable.
+func (m *DbMap) CreateTablesIfNotExists() error {
+	for _, table := range m.tables {
+		if err := m.createTable(table, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateTables runs a CREATE TABLE for every table registered with
+// AddTable. Unlike CreateTablesIfNotExists, it errors if a table already
+// exists.
+func (m *DbMap) CreateTables() error {
+	for _, table := range m.tables {
+		if err := m.createTable(table, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *DbMap) createTable(table *TableMap, ifNotExists bool) error {
+	var defs []string
+	var pk []string
+	for _, col := range table.Columns {
+		if col.Transient {
+			continue
+		}
+		def := fmt.Sprintf("%s %s", m.Dialect.QuoteField(col.ColumnName), m.Dialect.ToSqlType(col.goType, col.MaxSize, col.isAutoIncr))
+		if col.isAutoIncr {
+			def += " " + m.Dialect.AutoIncrStr()
+		}
+		defs = append(defs, def)
+		if col.isPK {
+			pk = append(pk, m.Dialect.QuoteField(col.ColumnName))
+		}
+	}
+	if len(pk) > 0 {
+		defs = append(defs, fmt.Sprintf("primary key (%s)", strings.Join(pk, ", ")))
+	}
+
+	create := "create table"
+	if ifNotExists {
+		create = m.Dialect.IfTableNotExists(create, table.SchemaName, table.TableName)
+	}
+	create = fmt.Sprintf("%s %s (%s)%s", create,
+		m.Dialect.QuotedTableForQuery(table.SchemaName, table.TableName),
+		strings.Join(defs, ", "), m.Dialect.CreateTableSuffix())
+
+	_, err := m.Exec(create)
+	return err
+}
+
+func insert(ctx context.Context, m *DbMap, exec ctxExecutor, list ...interface{}) error {
+	for _, ptr := range list {
+		v := reflect.ValueOf(ptr)
+		if v.Kind() != reflect.Ptr {
+			return fmt.Errorf("gorp: Insert needs a pointer, got %T", ptr)
+		}
+		elem := v.Elem()
+		table, err := m.tableFor(elem.Type(), false)
+		if err != nil {
+			return err
+		}
+
+		if err := preInsert(ctx, ptr, exec); err != nil {
+			return err
+		}
+
+		var cols, binds []string
+		var args []interface{}
+		var autoIncr *ColumnMap
+		for _, col := range table.Columns {
+			if col.Transient {
+				continue
+			}
+			if col.isAutoIncr {
+				autoIncr = col
+				if bv := m.Dialect.AutoIncrBindValue(); bv != "" {
+					cols = append(cols, m.Dialect.QuoteField(col.ColumnName))
+					binds = append(binds, bv)
+				}
+				continue
+			}
+			cols = append(cols, m.Dialect.QuoteField(col.ColumnName))
+			binds = append(binds, m.Dialect.BindVar(len(args)))
+			args = append(args, elem.FieldByName(col.fieldName).Interface())
+		}
+
+		infix := ""
+		if autoIncr != nil {
+			if infixer, ok := m.Dialect.(AutoIncrInfixInserter); ok {
+				infix = infixer.AutoIncrInsertInfix(autoIncr)
+			}
+		}
+		query := fmt.Sprintf("insert into %s (%s)%s values (%s)",
+			m.Dialect.QuotedTableForQuery(table.SchemaName, table.TableName),
+			strings.Join(cols, ", "), infix, strings.Join(binds, ", "))
+
+		if autoIncr != nil {
+			field := elem.FieldByName(autoIncr.fieldName)
+			switch inserter := m.Dialect.(type) {
+			case TargetedAutoIncrInserter:
+				target := reflect.New(field.Type())
+				full := query + m.Dialect.AutoIncrInsertSuffix(autoIncr)
+				if err := inserter.InsertAutoIncrToTarget(exec, full, target.Interface(), args...); err != nil {
+					return err
+				}
+				field.Set(target.Elem())
+			case IntegerAutoIncrInserter:
+				id, err := inserter.InsertAutoIncr(exec, query, args...)
+				if err != nil {
+					return err
+				}
+				field.SetInt(id)
+			default:
+				if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+					return err
+				}
+			}
+		} else if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		if err := postInsert(ctx, ptr, exec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func update(ctx context.Context, m *DbMap, exec ctxExecutor, list ...interface{}) (int64, error) {
+	var count int64
+	for _, ptr := range list {
+		elem := reflect.ValueOf(ptr).Elem()
+		table, err := m.tableFor(elem.Type(), true)
+		if err != nil {
+			return count, err
+		}
+
+		if err := preUpdate(ctx, ptr, exec); err != nil {
+			return count, err
+		}
+
+		var sets []string
+		var args []interface{}
+		for _, col := range table.Columns {
+			if col.Transient || col.isPK {
+				continue
+			}
+			sets = append(sets, fmt.Sprintf("%s = %s", m.Dialect.QuoteField(col.ColumnName), m.Dialect.BindVar(len(args))))
+			args = append(args, elem.FieldByName(col.fieldName).Interface())
+		}
+
+		var wheres []string
+		for _, col := range table.keys {
+			wheres = append(wheres, fmt.Sprintf("%s = %s", m.Dialect.QuoteField(col.ColumnName), m.Dialect.BindVar(len(args))))
+			args = append(args, elem.FieldByName(col.fieldName).Interface())
+		}
+
+		query := fmt.Sprintf("update %s set %s where %s",
+			m.Dialect.QuotedTableForQuery(table.SchemaName, table.TableName),
+			strings.Join(sets, ", "), strings.Join(wheres, " and "))
+
+		res, err := exec.ExecContext(ctx, query, args...)
+		if err != nil {
+			return count, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return count, err
+		}
+		count += n
+
+		if err := postUpdate(ctx, ptr, exec); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func deleteRows(ctx context.Context, m *DbMap, exec ctxExecutor, list ...interface{}) (int64, error) {
+	var count int64
+	for _, ptr := range list {
+		elem := reflect.ValueOf(ptr).Elem()
+		table, err := m.tableFor(elem.Type(), true)
+		if err != nil {
+			return count, err
+		}
+
+		if err := preDelete(ctx, ptr, exec); err != nil {
+			return count, err
+		}
+
+		var wheres []string
+		var args []interface{}
+		for _, col := range table.keys {
+			wheres = append(wheres, fmt.Sprintf("%s = %s", m.Dialect.QuoteField(col.ColumnName), m.Dialect.BindVar(len(args))))
+			args = append(args, elem.FieldByName(col.fieldName).Interface())
+		}
+
+		query := fmt.Sprintf("delete from %s where %s",
+			m.Dialect.QuotedTableForQuery(table.SchemaName, table.TableName), strings.Join(wheres, " and "))
+
+		res, err := exec.ExecContext(ctx, query, args...)
+		if err != nil {
+			return count, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return count, err
+		}
+		count += n
+
+		if err := postDelete(ctx, ptr, exec); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func get(ctx context.Context, m *DbMap, exec ctxExecutor, i interface{}, keys ...interface{}) (interface{}, error) {
+	t := reflect.TypeOf(i)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	table, err := m.tableFor(t, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != len(table.keys) {
+		return nil, fmt.Errorf("gorp: Get called with %d keys, table %s has %d", len(keys), table.TableName, len(table.keys))
+	}
+
+	var wheres []string
+	for idx, col := range table.keys {
+		wheres = append(wheres, fmt.Sprintf("%s = %s", m.Dialect.QuoteField(col.ColumnName), m.Dialect.BindVar(idx)))
+	}
+	var cols []string
+	for _, col := range table.Columns {
+		if col.Transient {
+			continue
+		}
+		cols = append(cols, m.Dialect.QuoteField(col.ColumnName))
+	}
+
+	query := fmt.Sprintf("select %s from %s where %s",
+		strings.Join(cols, ", "), m.Dialect.QuotedTableForQuery(table.SchemaName, table.TableName), strings.Join(wheres, " and "))
+
+	dest := reflect.New(t)
+	elemV := dest.Elem()
+	ptrs := make([]interface{}, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		if col.Transient {
+			continue
+		}
+		ptrs = append(ptrs, elemV.FieldByName(col.fieldName).Addr().Interface())
+	}
+
+	row := exec.QueryRowContext(ctx, query, keys...)
+	if err := row.Scan(ptrs...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := dest.Interface()
+	if err := postGet(ctx, result, exec); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// selectRows runs query and scans every row into a new value of i's type,
+// matching result columns to struct fields by name (via `db:"..."` tags,
+// same as AddTable) rather than by the type's registered TableMap — i
+// doesn't need to have been passed to AddTable at all. Columns the query
+// returns with no matching field are discarded; struct fields with no
+// matching column are left zero.
+func selectRows(ctx context.Context, exec ctxExecutor, i interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	t := reflect.TypeOf(i)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fieldForCol := make(map[string]int, t.NumField())
+	for idx := 0; idx < t.NumField(); idx++ {
+		f := t.Field(idx)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("db"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fieldForCol[strings.ToLower(name)] = idx
+	}
+
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	colNames, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []interface{}
+	for rows.Next() {
+		dest := reflect.New(t)
+		elem := dest.Elem()
+		ptrs := make([]interface{}, len(colNames))
+		for i, name := range colNames {
+			if fieldIdx, ok := fieldForCol[strings.ToLower(name)]; ok {
+				ptrs[i] = elem.Field(fieldIdx).Addr().Interface()
+			} else {
+				var ignore interface{}
+				ptrs[i] = &ignore
+			}
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		results = append(results, dest.Interface())
+	}
+	return results, rows.Err()
+}
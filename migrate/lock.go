@@ -0,0 +1,121 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mmrath/gorp"
+)
+
+// sqliteLockTable holds a single sentinel row for the lifetime of an
+// Apply run, giving acquireLock's SQLite branch something to take a
+// unique-constraint lock on without holding a transaction open.
+const sqliteLockTable = "gorp_migrate_lock"
+
+// acquireLock takes a per-dialect advisory lock so that two processes
+// migrating the same database serialize rather than race, and returns a
+// function that releases it. Dialects with no advisory lock support (and
+// any dialect not listed below) fall back to a no-op: callers running a
+// single migrator at a time are unaffected.
+//
+// pg_advisory_lock/unlock and MySQL's GET_LOCK/RELEASE_LOCK are scoped to
+// the session that took them; running the acquire and release through
+// dbmap.Db directly would let database/sql hand them to different pooled
+// connections, so the lock would never actually be released by the
+// connection holding it. A single *sql.Conn is pinned for the lock's
+// lifetime to avoid that.
+//
+// SQLite has no session-scoped advisory lock to pin a connection to in
+// the first place, and its only real mutual-exclusion primitive -
+// BEGIN EXCLUSIVE - is itself an open transaction: holding one for the
+// lock's duration would collide with Migrator.Apply's own per-migration
+// transactions the moment they ran on a different pooled connection (the
+// single-writer database would see two transactions and serialize or
+// error instead of letting the migration through). So SQLite instead
+// takes the lock via a sentinel row in a dedicated table, inserted and
+// deleted through the ordinary connection pool like any other statement;
+// the insert's primary key collision is what provides mutual exclusion,
+// not an open transaction.
+func acquireLock(dbmap *gorp.DbMap) (release func() error, err error) {
+	ctx := context.Background()
+
+	switch dbmap.Dialect.(type) {
+	case gorp.PostgresDialect:
+		conn, err := dbmap.Db.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := conn.ExecContext(ctx, "select pg_advisory_lock($1)", lockID); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return func() error {
+			_, err := conn.ExecContext(ctx, "select pg_advisory_unlock($1)", lockID)
+			return closeAfter(conn, err)
+		}, nil
+
+	case gorp.MySQLDialect:
+		conn, err := dbmap.Db.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		name := "gorp_migrate"
+		var got int
+		if err := conn.QueryRowContext(ctx, "select get_lock(?, -1)", name).Scan(&got); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return func() error {
+			_, err := conn.ExecContext(ctx, "select release_lock(?)", name)
+			return closeAfter(conn, err)
+		}, nil
+
+	case gorp.SqliteDialect:
+		return acquireSqliteLock(ctx, dbmap)
+
+	default:
+		return func() error { return nil }, nil
+	}
+}
+
+// acquireSqliteLock busy-waits on inserting a sentinel row into
+// sqliteLockTable, which a concurrent migrator will already hold; the
+// table's primary key rejects the second insert with a constraint error
+// rather than blocking, so this polls instead of waiting on the driver.
+// Every statement goes through dbmap's ordinary pool: there's no open
+// transaction here for a pooled connection to collide with.
+func acquireSqliteLock(ctx context.Context, dbmap *gorp.DbMap) (release func() error, err error) {
+	create := fmt.Sprintf("create table if not exists %s (id integer primary key check (id = 1))", sqliteLockTable)
+	if _, err := dbmap.ExecContext(ctx, create); err != nil {
+		return nil, err
+	}
+
+	dialect := dbmap.Dialect.(gorp.SqliteDialect)
+	insert := fmt.Sprintf("insert into %s (id) values (1)", sqliteLockTable)
+	for {
+		if _, err := dbmap.ExecContext(ctx, insert); err != nil {
+			if dialect.IsConstraintError(err) {
+				time.Sleep(25 * time.Millisecond)
+				continue
+			}
+			return nil, err
+		}
+		break
+	}
+
+	return func() error {
+		_, err := dbmap.ExecContext(ctx, fmt.Sprintf("delete from %s where id = 1", sqliteLockTable))
+		return err
+	}, nil
+}
+
+// closeAfter closes conn, returning the first non-nil of releaseErr and
+// the close error so a release failure is never silently swallowed.
+func closeAfter(conn interface{ Close() error }, releaseErr error) error {
+	closeErr := conn.Close()
+	if releaseErr != nil {
+		return releaseErr
+	}
+	return closeErr
+}
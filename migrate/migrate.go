@@ -0,0 +1,327 @@
+// Package migrate is a schema migration subsystem built on top of gorp's
+// Dialect/DbMap abstraction, so migrations share the same dialect handling
+// (quoting, advisory locks) as the rest of a gorp-mapped application.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mmrath/gorp"
+)
+
+// migrationsTable is the metadata table migrate uses to record which
+// migrations have been applied.
+const migrationsTable = "gorp_migrations"
+
+// Direction selects which way Migrator.Apply walks the migration list.
+type Direction int
+
+const (
+	Up Direction = iota
+	Down
+)
+
+// Migration is a single reversible schema change. Up is required; Down may
+// be nil for migrations that are not meant to be rolled back.
+//
+// Up and Down receive a gorp.SqlExecutor rather than a concrete
+// *gorp.Transaction so the same function works whether or not the
+// migration participates in a transaction: Apply passes a *gorp.Transaction
+// when Transactional is true, and the *gorp.DbMap itself otherwise.
+type Migration struct {
+	ID   string
+	Up   func(gorp.SqlExecutor) error
+	Down func(gorp.SqlExecutor) error
+
+	// Transactional makes Apply run the step and its bookkeeping in one
+	// transaction. FromSQL and FromFS set it to true; a Migration written
+	// as a struct literal has it false unless set there. Leave it false
+	// for statements a driver refuses to run inside a transaction, e.g.
+	// SQLite's "PRAGMA foreign_keys" or MySQL's implicit-commit DDL.
+	Transactional bool
+
+	// Checksum is recorded alongside the applied migration. Status returns
+	// the recorded value for applied migrations and the current one for
+	// pending migrations; it does not compare the two.
+	// FromSQL and FromFS populate it automatically; hand-written
+	// func-based migrations may set it themselves or leave it blank.
+	Checksum string
+}
+
+// FromSQL builds a Migration that runs upSQL and downSQL verbatim via
+// exec.Exec.
+func FromSQL(id, upSQL, downSQL string) Migration {
+	m := Migration{
+		ID: id,
+		Up: func(exec gorp.SqlExecutor) error {
+			_, err := exec.Exec(upSQL)
+			return err
+		},
+		Transactional: true,
+		Checksum:      checksum(upSQL, downSQL),
+	}
+	if strings.TrimSpace(downSQL) != "" {
+		m.Down = func(exec gorp.SqlExecutor) error {
+			_, err := exec.Exec(downSQL)
+			return err
+		}
+	}
+	return m
+}
+
+// FromFS collects migrations from fsys, pairing files matching
+// "<dir>/*.up.sql" with their "*.down.sql" counterpart by shared prefix,
+// and returns them sorted by ID. It is meant to be used with a Go 1.16
+// embed.FS:
+//
+//	//go:embed migrations/*.sql
+//	var migrationsFS embed.FS
+//
+//	migrations, err := migrate.FromFS(migrationsFS, "migrations")
+func FromFS(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: reading %s: %w", dir, err)
+	}
+
+	ups := map[string]string{}
+	downs := map[string]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var id string
+		var isUp bool
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			id, isUp = strings.TrimSuffix(name, ".up.sql"), true
+		case strings.HasSuffix(name, ".down.sql"):
+			id = strings.TrimSuffix(name, ".down.sql")
+		default:
+			continue
+		}
+
+		b, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: reading %s: %w", name, err)
+		}
+		if isUp {
+			ups[id] = string(b)
+		} else {
+			downs[id] = string(b)
+		}
+	}
+
+	ids := make([]string, 0, len(ups))
+	for id := range ups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	migrations := make([]Migration, 0, len(ids))
+	for _, id := range ids {
+		migrations = append(migrations, FromSQL(id, ups[id], downs[id]))
+	}
+	return migrations, nil
+}
+
+func checksum(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Status describes one migration's applied state.
+type Status struct {
+	ID        string    `db:"id"`
+	Applied   bool      `db:"-"`
+	AppliedAt time.Time `db:"applied_at"`
+	Checksum  string    `db:"checksum"`
+}
+
+// Migrator applies an ordered list of Migrations to a gorp.DbMap.
+type Migrator struct {
+	Migrations []Migration
+}
+
+// New returns a Migrator for the given migrations, which must already be in
+// the order they should be applied.
+func New(migrations ...Migration) *Migrator {
+	return &Migrator{Migrations: migrations}
+}
+
+// Apply runs pending (direction Up) or applied (direction Down) migrations
+// against dbmap, up to count of them. count <= 0 means "all of them". It
+// returns the IDs of the migrations it ran, in the order they ran.
+//
+// A per-dialect advisory lock is held for the duration of Apply so that
+// concurrent processes migrating the same database serialize rather than
+// race; see lock.go.
+func (m *Migrator) Apply(dbmap *gorp.DbMap, direction Direction, count int) ([]string, error) {
+	if err := ensureMigrationsTable(dbmap); err != nil {
+		return nil, err
+	}
+
+	release, err := acquireLock(dbmap)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: acquiring lock: %w", err)
+	}
+	defer release()
+
+	applied, err := appliedIDs(dbmap)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := m.pendingInOrder(applied, direction)
+	if count > 0 && count < len(pending) {
+		pending = pending[:count]
+	}
+
+	var ran []string
+	for _, mig := range pending {
+		if err := m.runOne(dbmap, mig, direction); err != nil {
+			return ran, fmt.Errorf("migrate: %s: %w", mig.ID, err)
+		}
+		ran = append(ran, mig.ID)
+	}
+	return ran, nil
+}
+
+func (m *Migrator) pendingInOrder(applied map[string]bool, direction Direction) []Migration {
+	var pending []Migration
+	if direction == Up {
+		for _, mig := range m.Migrations {
+			if !applied[mig.ID] {
+				pending = append(pending, mig)
+			}
+		}
+		return pending
+	}
+
+	for i := len(m.Migrations) - 1; i >= 0; i-- {
+		mig := m.Migrations[i]
+		if applied[mig.ID] {
+			pending = append(pending, mig)
+		}
+	}
+	return pending
+}
+
+func (m *Migrator) runOne(dbmap *gorp.DbMap, mig Migration, direction Direction) error {
+	step := mig.Up
+	if direction == Down {
+		step = mig.Down
+	}
+	if step == nil {
+		return fmt.Errorf("no %s step defined", directionName(direction))
+	}
+
+	if !mig.Transactional {
+		if err := step(dbmap); err != nil {
+			return err
+		}
+		return recordMigration(dbmap, dbmap.Dialect, mig, direction)
+	}
+
+	tx, err := dbmap.Begin()
+	if err != nil {
+		return err
+	}
+	if err := step(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := recordMigration(tx, dbmap.Dialect, mig, direction); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func recordMigration(exec gorp.SqlExecutor, dialect gorp.Dialect, mig Migration, direction Direction) error {
+	if direction == Up {
+		query := fmt.Sprintf("insert into %s (id, applied_at, checksum) values (%s, %s, %s)",
+			migrationsTable, dialect.BindVar(0), dialect.BindVar(1), dialect.BindVar(2))
+		_, err := exec.Exec(query, mig.ID, time.Now().UTC(), mig.Checksum)
+		return err
+	}
+	query := fmt.Sprintf("delete from %s where id = %s", migrationsTable, dialect.BindVar(0))
+	_, err := exec.Exec(query, mig.ID)
+	return err
+}
+
+func directionName(d Direction) string {
+	if d == Down {
+		return "down"
+	}
+	return "up"
+}
+
+func appliedIDs(dbmap *gorp.DbMap) (map[string]bool, error) {
+	rows, err := dbmap.Select(Status{}, fmt.Sprintf("select id, applied_at, checksum from %s", migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		applied[row.(*Status).ID] = true
+	}
+	return applied, nil
+}
+
+// Status reports, for every migration registered with m, whether it has
+// been applied to dbmap and when.
+func (m *Migrator) Status(dbmap *gorp.DbMap) ([]Status, error) {
+	if err := ensureMigrationsTable(dbmap); err != nil {
+		return nil, err
+	}
+
+	rows, err := dbmap.Select(Status{}, fmt.Sprintf("select id, applied_at, checksum from %s", migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Status, len(rows))
+	for _, row := range rows {
+		s := row.(*Status)
+		s.Applied = true
+		byID[s.ID] = s
+	}
+
+	statuses := make([]Status, 0, len(m.Migrations))
+	for _, mig := range m.Migrations {
+		if s, ok := byID[mig.ID]; ok {
+			statuses = append(statuses, *s)
+			continue
+		}
+		statuses = append(statuses, Status{ID: mig.ID, Checksum: mig.Checksum})
+	}
+	return statuses, nil
+}
+
+func ensureMigrationsTable(dbmap *gorp.DbMap) error {
+	create := dbmap.Dialect.IfTableNotExists("create table", "", migrationsTable)
+	_, err := dbmap.Exec(fmt.Sprintf(
+		"%s %s (id varchar(255) primary key, applied_at timestamp not null, checksum varchar(64) not null)",
+		create, dbmap.Dialect.QuotedTableForQuery("", migrationsTable),
+	))
+	return err
+}
+
+// lockID is a stable advisory-lock key derived from the package path.
+// Every migrator using this package takes the same key, so concurrent
+// Apply runs against one database serialize even when they belong to
+// unrelated applications.
+var lockID = func() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("gorp/migrate"))
+	return int64(h.Sum64())
+}()
@@ -0,0 +1,94 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestChecksum_StableAndSensitiveToContent(t *testing.T) {
+	a := checksum("up", "down")
+	b := checksum("up", "down")
+	if a != b {
+		t.Fatalf("checksum should be deterministic: %q != %q", a, b)
+	}
+	if c := checksum("up", "different down"); c == a {
+		t.Fatal("checksum should change when the migration's SQL changes")
+	}
+}
+
+func TestMigrator_PendingInOrder_Up(t *testing.T) {
+	m := New(
+		Migration{ID: "1"},
+		Migration{ID: "2"},
+		Migration{ID: "3"},
+	)
+	applied := map[string]bool{"1": true}
+
+	pending := m.pendingInOrder(applied, Up)
+
+	var ids []string
+	for _, mig := range pending {
+		ids = append(ids, mig.ID)
+	}
+	if got, want := ids, []string{"2", "3"}; !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestMigrator_PendingInOrder_Down(t *testing.T) {
+	m := New(
+		Migration{ID: "1"},
+		Migration{ID: "2"},
+		Migration{ID: "3"},
+	)
+	applied := map[string]bool{"1": true, "2": true}
+
+	pending := m.pendingInOrder(applied, Down)
+
+	var ids []string
+	for _, mig := range pending {
+		ids = append(ids, mig.ID)
+	}
+	// Down must unwind in reverse order: "2" before "1".
+	if got, want := ids, []string{"2", "1"}; !equalStrings(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFromFS_PairsUpAndDownByPrefix(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0001_init.up.sql":    {Data: []byte("create table foo (id int)")},
+		"migrations/0001_init.down.sql":  {Data: []byte("drop table foo")},
+		"migrations/0002_add_col.up.sql": {Data: []byte("alter table foo add bar int")},
+		"migrations/not_a_migration.txt": {Data: []byte("ignore me")},
+	}
+
+	migrations, err := FromFS(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("FromFS: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("got %d migrations, want 2", len(migrations))
+	}
+	if migrations[0].ID != "0001_init" || migrations[1].ID != "0002_add_col" {
+		t.Fatalf("migrations not sorted/paired as expected: %+v", migrations)
+	}
+	if migrations[0].Down == nil {
+		t.Error("0001_init should have a Down step paired from its .down.sql file")
+	}
+	if migrations[1].Down != nil {
+		t.Error("0002_add_col has no .down.sql file and should have a nil Down step")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
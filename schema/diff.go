@@ -0,0 +1,148 @@
+package schema
+
+import "fmt"
+
+// ChangeKind categorizes a single entry returned by Diff.
+type ChangeKind int
+
+const (
+	AddTable ChangeKind = iota
+	DropTable
+	AddColumn
+	DropColumn
+	AlterColumn
+	AddIndex
+	DropIndex
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case AddTable:
+		return "add table"
+	case DropTable:
+		return "drop table"
+	case AddColumn:
+		return "add column"
+	case DropColumn:
+		return "drop column"
+	case AlterColumn:
+		return "alter column"
+	case AddIndex:
+		return "add index"
+	case DropIndex:
+		return "drop index"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is one difference between a registered schema (what the
+// application expects, e.g. from AddTableWithName) and a live one (what
+// Introspect found). Column/Index are empty for table-level changes.
+type Change struct {
+	Kind   ChangeKind
+	Table  string
+	Column string
+	Index  string
+	Detail string
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Column != "":
+		return fmt.Sprintf("%s: %s.%s (%s)", c.Kind, c.Table, c.Column, c.Detail)
+	case c.Index != "":
+		return fmt.Sprintf("%s: %s.%s (%s)", c.Kind, c.Table, c.Index, c.Detail)
+	default:
+		return fmt.Sprintf("%s: %s", c.Kind, c.Table)
+	}
+}
+
+// Diff compares registered (what the application expects to exist) against
+// live (what Introspect found), and returns every difference needed to
+// bring live in line with registered: tables/columns/indexes present in
+// registered but missing from live are adds, present in live but missing
+// from registered are drops, and columns present in both with a different
+// type or nullability are alters. Order is registered-table order, then
+// column/index order within each table.
+func Diff(registered, live *Schema) []Change {
+	var changes []Change
+
+	liveByName := make(map[string]Table, len(live.Tables))
+	for _, t := range live.Tables {
+		liveByName[t.Name] = t
+	}
+	seen := make(map[string]bool, len(registered.Tables))
+
+	for _, want := range registered.Tables {
+		seen[want.Name] = true
+		have, ok := liveByName[want.Name]
+		if !ok {
+			changes = append(changes, Change{Kind: AddTable, Table: want.Name})
+			continue
+		}
+		changes = append(changes, diffColumns(want, have)...)
+		changes = append(changes, diffIndexes(want, have)...)
+	}
+
+	for _, have := range live.Tables {
+		if !seen[have.Name] {
+			changes = append(changes, Change{Kind: DropTable, Table: have.Name})
+		}
+	}
+
+	return changes
+}
+
+func diffColumns(want, have Table) []Change {
+	var changes []Change
+	haveByName := make(map[string]Column, len(have.Columns))
+	for _, c := range have.Columns {
+		haveByName[c.Name] = c
+	}
+	seen := make(map[string]bool, len(want.Columns))
+
+	for _, wc := range want.Columns {
+		seen[wc.Name] = true
+		hc, ok := haveByName[wc.Name]
+		if !ok {
+			changes = append(changes, Change{Kind: AddColumn, Table: want.Name, Column: wc.Name, Detail: wc.Type})
+			continue
+		}
+		if hc.Type != wc.Type || hc.Nullable != wc.Nullable {
+			changes = append(changes, Change{
+				Kind: AlterColumn, Table: want.Name, Column: wc.Name,
+				Detail: fmt.Sprintf("have %s (nullable=%t), want %s (nullable=%t)", hc.Type, hc.Nullable, wc.Type, wc.Nullable),
+			})
+		}
+	}
+
+	for _, hc := range have.Columns {
+		if !seen[hc.Name] {
+			changes = append(changes, Change{Kind: DropColumn, Table: want.Name, Column: hc.Name})
+		}
+	}
+	return changes
+}
+
+func diffIndexes(want, have Table) []Change {
+	var changes []Change
+	haveByName := make(map[string]Index, len(have.Indexes))
+	for _, ix := range have.Indexes {
+		haveByName[ix.Name] = ix
+	}
+	seen := make(map[string]bool, len(want.Indexes))
+
+	for _, wix := range want.Indexes {
+		seen[wix.Name] = true
+		if _, ok := haveByName[wix.Name]; !ok {
+			changes = append(changes, Change{Kind: AddIndex, Table: want.Name, Index: wix.Name})
+		}
+	}
+	for _, hix := range have.Indexes {
+		if !seen[hix.Name] {
+			changes = append(changes, Change{Kind: DropIndex, Table: want.Name, Index: hix.Name})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,97 @@
+package schema
+
+import "testing"
+
+func TestDiff_AddDropAlter(t *testing.T) {
+	registered := &Schema{
+		Tables: []Table{
+			{
+				Name: "widgets",
+				Columns: []Column{
+					{Name: "id", Type: "integer", IsPrimary: true},
+					{Name: "name", Type: "varchar(255)"},
+					{Name: "price", Type: "numeric"},
+				},
+				Indexes: []Index{{Name: "widgets_name_idx", Columns: []string{"name"}}},
+			},
+			{Name: "new_table"},
+		},
+	}
+	live := &Schema{
+		Tables: []Table{
+			{
+				Name: "widgets",
+				Columns: []Column{
+					{Name: "id", Type: "integer", IsPrimary: true},
+					{Name: "name", Type: "varchar(255)"},
+					{Name: "legacy_col", Type: "text"},
+				},
+				Indexes: []Index{{Name: "widgets_legacy_idx", Columns: []string{"legacy_col"}}},
+			},
+			{Name: "stale_table"},
+		},
+	}
+
+	changes := Diff(registered, live)
+
+	has := func(kind ChangeKind, table, name string) bool {
+		for _, c := range changes {
+			if c.Kind != kind || c.Table != table {
+				continue
+			}
+			if c.Column == name || c.Index == name || name == "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !has(AddTable, "new_table", "") {
+		t.Error("expected an AddTable change for new_table")
+	}
+	if !has(DropTable, "stale_table", "") {
+		t.Error("expected a DropTable change for stale_table")
+	}
+	if !has(AddColumn, "widgets", "price") {
+		t.Error("expected an AddColumn change for widgets.price")
+	}
+	if !has(DropColumn, "widgets", "legacy_col") {
+		t.Error("expected a DropColumn change for widgets.legacy_col")
+	}
+	if !has(AddIndex, "widgets", "widgets_name_idx") {
+		t.Error("expected an AddIndex change for widgets_name_idx")
+	}
+	if !has(DropIndex, "widgets", "widgets_legacy_idx") {
+		t.Error("expected a DropIndex change for widgets_legacy_idx")
+	}
+	if has(AlterColumn, "widgets", "id") || has(AlterColumn, "widgets", "name") {
+		t.Error("unchanged columns should not produce an AlterColumn change")
+	}
+}
+
+func TestDiff_AlterColumn_TypeOrNullabilityChange(t *testing.T) {
+	registered := &Schema{Tables: []Table{{
+		Name:    "widgets",
+		Columns: []Column{{Name: "price", Type: "numeric(10,2)", Nullable: false}},
+	}}}
+	live := &Schema{Tables: []Table{{
+		Name:    "widgets",
+		Columns: []Column{{Name: "price", Type: "real", Nullable: true}},
+	}}}
+
+	changes := Diff(registered, live)
+	if len(changes) != 1 || changes[0].Kind != AlterColumn || changes[0].Column != "price" {
+		t.Fatalf("expected a single AlterColumn change for price, got %+v", changes)
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	s := &Schema{Tables: []Table{{
+		Name:    "widgets",
+		Columns: []Column{{Name: "id", Type: "integer", IsPrimary: true}},
+		Indexes: []Index{{Name: "widgets_id_idx", Columns: []string{"id"}}},
+	}}}
+	if changes := Diff(s, s); len(changes) != 0 {
+		t.Fatalf("expected no changes diffing a schema against itself, got %+v", changes)
+	}
+}
@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mmrath/gorp"
+)
+
+// Dump renders s as a dialect-specific "CREATE TABLE"/"CREATE INDEX"
+// script, in table order: tables with their columns, nullability and
+// primary key, then their indexes. Foreign keys and column defaults are
+// not written. Column types are emitted as introspection found them, not
+// translated to another engine's types; on MySQL, Postgres and SQL Server
+// that is the catalog's data_type, which carries no length or precision,
+// so a varchar(255) column is written as plain varchar.
+func Dump(s *Schema, dialect gorp.Dialect) string {
+	var b strings.Builder
+	for _, t := range s.Tables {
+		dumpTable(&b, t, dialect)
+	}
+	return b.String()
+}
+
+func dumpTable(b *strings.Builder, t Table, dialect gorp.Dialect) {
+	fmt.Fprintf(b, "create table %s (\n", dialect.QuotedTableForQuery("", t.Name))
+
+	var pk []string
+	for i, c := range t.Columns {
+		sep := ","
+		if i == len(t.Columns)-1 {
+			sep = ""
+		}
+		null := "not null"
+		if c.Nullable {
+			null = "null"
+		}
+		fmt.Fprintf(b, "\t%s %s %s%s\n", dialect.QuoteField(c.Name), c.Type, null, sep)
+		if c.IsPrimary {
+			pk = append(pk, dialect.QuoteField(c.Name))
+		}
+	}
+	if len(pk) > 0 {
+		fmt.Fprintf(b, ",\n\tprimary key (%s)", strings.Join(pk, ", "))
+	}
+	fmt.Fprintf(b, ")%s%s\n\n", dialect.CreateTableSuffix(), dialect.QuerySuffix())
+
+	for _, ix := range t.Indexes {
+		unique := ""
+		if ix.Unique {
+			unique = "unique "
+		}
+		cols := make([]string, len(ix.Columns))
+		for i, c := range ix.Columns {
+			cols[i] = dialect.QuoteField(c)
+		}
+		fmt.Fprintf(b, "create %sindex %s on %s (%s)%s\n\n",
+			unique, dialect.QuoteField(ix.Name), dialect.QuotedTableForQuery("", t.Name),
+			strings.Join(cols, ", "), dialect.QuerySuffix())
+	}
+}
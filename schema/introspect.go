@@ -0,0 +1,53 @@
+package schema
+
+import "github.com/mmrath/gorp"
+
+// Introspect reads dbmap's live database structure — tables, columns,
+// indexes and foreign keys — and returns it as a Schema. It dispatches on
+// the concrete type of dbmap.Dialect, so it supports exactly the dialects
+// this package has an introspector for (MySQL, Postgres, SQL Server and
+// SQLite as of this writing); any other Dialect returns an error.
+func Introspect(dbmap *gorp.DbMap) (*Schema, error) {
+	intro, err := introspectorFor(dbmap.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := intro.tableNames(dbmap)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schema{Tables: make([]Table, 0, len(names))}
+	for _, name := range names {
+		cols, err := intro.columns(dbmap, name)
+		if err != nil {
+			return nil, err
+		}
+		idxs, err := intro.indexes(dbmap, name)
+		if err != nil {
+			return nil, err
+		}
+		fks, err := intro.foreignKeys(dbmap, name)
+		if err != nil {
+			return nil, err
+		}
+		s.Tables = append(s.Tables, Table{Name: name, Columns: cols, Indexes: idxs, ForeignKeys: fks})
+	}
+	return s, nil
+}
+
+func introspectorFor(dialect gorp.Dialect) (introspector, error) {
+	switch dialect.(type) {
+	case gorp.MySQLDialect:
+		return mysqlIntrospector{}, nil
+	case gorp.PostgresDialect:
+		return postgresIntrospector{}, nil
+	case gorp.SqlServerDialect:
+		return mssqlIntrospector{}, nil
+	case gorp.SqliteDialect:
+		return sqliteIntrospector{}, nil
+	default:
+		return nil, unsupportedDialectError{dialect: dialect}
+	}
+}
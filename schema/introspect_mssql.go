@@ -0,0 +1,133 @@
+package schema
+
+type mssqlIntrospector struct{}
+
+func (mssqlIntrospector) tableNames(exec queryer) ([]string, error) {
+	type row struct {
+		Name string `db:"table_name"`
+	}
+	rows, err := exec.Select(row{}, `
+		select table_name from information_schema.tables
+		where table_type = 'BASE TABLE'
+		order by table_name`)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(rows))
+	for i, r := range rows {
+		names[i] = r.(*row).Name
+	}
+	return names, nil
+}
+
+func (mssqlIntrospector) columns(exec queryer, table string) ([]Column, error) {
+	type row struct {
+		Name     string `db:"column_name"`
+		Type     string `db:"data_type"`
+		Nullable string `db:"is_nullable"`
+		Default  string `db:"column_default"`
+	}
+	rows, err := exec.Select(row{}, `
+		select column_name, data_type, is_nullable, coalesce(column_default, '') as column_default
+		from information_schema.columns
+		where table_name = @p1
+		order by ordinal_position`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	pk := make(map[string]bool)
+	type pkRow struct {
+		Name string `db:"column_name"`
+	}
+	pkRows, err := exec.Select(pkRow{}, `
+		select kcu.column_name
+		from information_schema.table_constraints tc
+		join information_schema.key_column_usage kcu
+			on kcu.constraint_name = tc.constraint_name
+		where tc.table_name = @p1 and tc.constraint_type = 'PRIMARY KEY'`, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range pkRows {
+		pk[r.(*pkRow).Name] = true
+	}
+
+	cols := make([]Column, len(rows))
+	for i, r := range rows {
+		c := r.(*row)
+		cols[i] = Column{
+			Name:      c.Name,
+			Type:      c.Type,
+			Nullable:  c.Nullable == "YES",
+			Default:   c.Default,
+			IsPrimary: pk[c.Name],
+		}
+	}
+	return cols, nil
+}
+
+func (mssqlIntrospector) indexes(exec queryer, table string) ([]Index, error) {
+	type row struct {
+		Name   string `db:"index_name"`
+		Column string `db:"column_name"`
+		Unique bool   `db:"is_unique"`
+	}
+	rows, err := exec.Select(row{}, `
+		select i.name as index_name, c.name as column_name, i.is_unique
+		from sys.indexes i
+		join sys.index_columns ic on ic.object_id = i.object_id and ic.index_id = i.index_id
+		join sys.columns c on c.object_id = ic.object_id and c.column_id = ic.column_id
+		join sys.tables t on t.object_id = i.object_id
+		where t.name = @p1 and i.is_primary_key = 0
+		order by i.name, ic.key_ordinal`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var idxs []Index
+	byName := map[string]*Index{}
+	for _, r := range rows {
+		s := r.(*row)
+		ix, ok := byName[s.Name]
+		if !ok {
+			idxs = append(idxs, Index{Name: s.Name, Unique: s.Unique})
+			ix = &idxs[len(idxs)-1]
+			byName[s.Name] = ix
+		}
+		ix.Columns = append(ix.Columns, s.Column)
+	}
+	return idxs, nil
+}
+
+func (mssqlIntrospector) foreignKeys(exec queryer, table string) ([]ForeignKey, error) {
+	type row struct {
+		Name      string `db:"constraint_name"`
+		Column    string `db:"column_name"`
+		RefTable  string `db:"ref_table"`
+		RefColumn string `db:"ref_column"`
+	}
+	rows, err := exec.Select(row{}, `
+		select
+			fk.name as constraint_name,
+			c.name as column_name,
+			rt.name as ref_table,
+			rc.name as ref_column
+		from sys.foreign_keys fk
+		join sys.foreign_key_columns fkc on fkc.constraint_object_id = fk.object_id
+		join sys.tables t on t.object_id = fk.parent_object_id
+		join sys.columns c on c.object_id = fkc.parent_object_id and c.column_id = fkc.parent_column_id
+		join sys.tables rt on rt.object_id = fk.referenced_object_id
+		join sys.columns rc on rc.object_id = fkc.referenced_object_id and rc.column_id = fkc.referenced_column_id
+		where t.name = @p1`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	fks := make([]ForeignKey, len(rows))
+	for i, r := range rows {
+		fk := r.(*row)
+		fks[i] = ForeignKey{Name: fk.Name, Column: fk.Column, RefTable: fk.RefTable, RefColumn: fk.RefColumn}
+	}
+	return fks, nil
+}
@@ -0,0 +1,105 @@
+package schema
+
+type mysqlIntrospector struct{}
+
+func (mysqlIntrospector) tableNames(exec queryer) ([]string, error) {
+	type row struct {
+		Name string `db:"table_name"`
+	}
+	rows, err := exec.Select(row{}, `
+		select table_name from information_schema.tables
+		where table_schema = database() and table_type = 'BASE TABLE'
+		order by table_name`)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(rows))
+	for i, r := range rows {
+		names[i] = r.(*row).Name
+	}
+	return names, nil
+}
+
+func (mysqlIntrospector) columns(exec queryer, table string) ([]Column, error) {
+	type row struct {
+		Name     string `db:"column_name"`
+		Type     string `db:"data_type"`
+		Nullable string `db:"is_nullable"`
+		Default  string `db:"column_default"`
+		Key      string `db:"column_key"`
+	}
+	rows, err := exec.Select(row{}, `
+		select column_name, data_type, is_nullable, coalesce(column_default, '') as column_default, column_key
+		from information_schema.columns
+		where table_schema = database() and table_name = ?
+		order by ordinal_position`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]Column, len(rows))
+	for i, r := range rows {
+		c := r.(*row)
+		cols[i] = Column{
+			Name:      c.Name,
+			Type:      c.Type,
+			Nullable:  c.Nullable == "YES",
+			Default:   c.Default,
+			IsPrimary: c.Key == "PRI",
+		}
+	}
+	return cols, nil
+}
+
+func (mysqlIntrospector) indexes(exec queryer, table string) ([]Index, error) {
+	type row struct {
+		Name    string `db:"index_name"`
+		Column  string `db:"column_name"`
+		NonUniq int    `db:"non_unique"`
+	}
+	rows, err := exec.Select(row{}, `
+		select index_name, column_name, non_unique
+		from information_schema.statistics
+		where table_schema = database() and table_name = ? and index_name != 'PRIMARY'
+		order by index_name, seq_in_index`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var idxs []Index
+	byName := map[string]*Index{}
+	for _, r := range rows {
+		s := r.(*row)
+		ix, ok := byName[s.Name]
+		if !ok {
+			idxs = append(idxs, Index{Name: s.Name, Unique: s.NonUniq == 0})
+			ix = &idxs[len(idxs)-1]
+			byName[s.Name] = ix
+		}
+		ix.Columns = append(ix.Columns, s.Column)
+	}
+	return idxs, nil
+}
+
+func (mysqlIntrospector) foreignKeys(exec queryer, table string) ([]ForeignKey, error) {
+	type row struct {
+		Name      string `db:"constraint_name"`
+		Column    string `db:"column_name"`
+		RefTable  string `db:"ref_table"`
+		RefColumn string `db:"ref_column"`
+	}
+	rows, err := exec.Select(row{}, `
+		select constraint_name, column_name, referenced_table_name as ref_table, referenced_column_name as ref_column
+		from information_schema.key_column_usage
+		where table_schema = database() and table_name = ? and referenced_table_name is not null`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	fks := make([]ForeignKey, len(rows))
+	for i, r := range rows {
+		fk := r.(*row)
+		fks[i] = ForeignKey{Name: fk.Name, Column: fk.Column, RefTable: fk.RefTable, RefColumn: fk.RefColumn}
+	}
+	return fks, nil
+}
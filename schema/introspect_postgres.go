@@ -0,0 +1,128 @@
+package schema
+
+type postgresIntrospector struct{}
+
+func (postgresIntrospector) tableNames(exec queryer) ([]string, error) {
+	type row struct {
+		Name string `db:"table_name"`
+	}
+	rows, err := exec.Select(row{}, `
+		select table_name from information_schema.tables
+		where table_schema = current_schema() and table_type = 'BASE TABLE'
+		order by table_name`)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(rows))
+	for i, r := range rows {
+		names[i] = r.(*row).Name
+	}
+	return names, nil
+}
+
+func (postgresIntrospector) columns(exec queryer, table string) ([]Column, error) {
+	type row struct {
+		Name     string `db:"column_name"`
+		Type     string `db:"data_type"`
+		Nullable string `db:"is_nullable"`
+		Default  string `db:"column_default"`
+	}
+	rows, err := exec.Select(row{}, `
+		select column_name, data_type, is_nullable, coalesce(column_default, '') as column_default
+		from information_schema.columns
+		where table_schema = current_schema() and table_name = $1
+		order by ordinal_position`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	pk := make(map[string]bool)
+	type pkRow struct {
+		Name string `db:"column_name"`
+	}
+	pkRows, err := exec.Select(pkRow{}, `
+		select kcu.column_name
+		from information_schema.table_constraints tc
+		join information_schema.key_column_usage kcu
+			on kcu.constraint_name = tc.constraint_name and kcu.table_schema = tc.table_schema
+		where tc.table_schema = current_schema() and tc.table_name = $1 and tc.constraint_type = 'PRIMARY KEY'`, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range pkRows {
+		pk[r.(*pkRow).Name] = true
+	}
+
+	cols := make([]Column, len(rows))
+	for i, r := range rows {
+		c := r.(*row)
+		cols[i] = Column{
+			Name:      c.Name,
+			Type:      c.Type,
+			Nullable:  c.Nullable == "YES",
+			Default:   c.Default,
+			IsPrimary: pk[c.Name],
+		}
+	}
+	return cols, nil
+}
+
+func (postgresIntrospector) indexes(exec queryer, table string) ([]Index, error) {
+	type row struct {
+		Name      string `db:"index_name"`
+		ColumnSQL string `db:"columns"`
+		Unique    bool   `db:"is_unique"`
+	}
+	rows, err := exec.Select(row{}, `
+		select
+			ix.relname as index_name,
+			string_agg(a.attname, ',' order by array_position(i.indkey, a.attnum)) as columns,
+			i.indisunique as is_unique
+		from pg_index i
+		join pg_class t on t.oid = i.indrelid
+		join pg_class ix on ix.oid = i.indexrelid
+		join pg_attribute a on a.attrelid = t.oid and a.attnum = any(i.indkey)
+		where t.relname = $1 and not i.indisprimary
+		group by ix.relname, i.indisunique`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	idxs := make([]Index, len(rows))
+	for i, r := range rows {
+		ix := r.(*row)
+		idxs[i] = Index{Name: ix.Name, Columns: splitCSV(ix.ColumnSQL), Unique: ix.Unique}
+	}
+	return idxs, nil
+}
+
+func (postgresIntrospector) foreignKeys(exec queryer, table string) ([]ForeignKey, error) {
+	type row struct {
+		Name      string `db:"constraint_name"`
+		Column    string `db:"column_name"`
+		RefTable  string `db:"ref_table"`
+		RefColumn string `db:"ref_column"`
+	}
+	rows, err := exec.Select(row{}, `
+		select
+			tc.constraint_name,
+			kcu.column_name,
+			ccu.table_name as ref_table,
+			ccu.column_name as ref_column
+		from information_schema.table_constraints tc
+		join information_schema.key_column_usage kcu
+			on kcu.constraint_name = tc.constraint_name and kcu.table_schema = tc.table_schema
+		join information_schema.constraint_column_usage ccu
+			on ccu.constraint_name = tc.constraint_name and ccu.table_schema = tc.table_schema
+		where tc.table_schema = current_schema() and tc.table_name = $1 and tc.constraint_type = 'FOREIGN KEY'`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	fks := make([]ForeignKey, len(rows))
+	for i, r := range rows {
+		fk := r.(*row)
+		fks[i] = ForeignKey{Name: fk.Name, Column: fk.Column, RefTable: fk.RefTable, RefColumn: fk.RefColumn}
+	}
+	return fks, nil
+}
@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type sqliteIntrospector struct{}
+
+func (sqliteIntrospector) tableNames(exec queryer) ([]string, error) {
+	type row struct {
+		Name string `db:"name"`
+	}
+	rows, err := exec.Select(row{}, `
+		select name from sqlite_master
+		where type = 'table' and name not like 'sqlite_%'
+		order by name`)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(rows))
+	for i, r := range rows {
+		names[i] = r.(*row).Name
+	}
+	return names, nil
+}
+
+// pragma runs a PRAGMA against table. SQLite's PRAGMA statements don't
+// accept bound parameters, so the table name — which always comes from a
+// prior sqlite_master query, never from external input — is escaped and
+// inlined instead.
+func pragma(name, table string) string {
+	return fmt.Sprintf("PRAGMA %s(%s)", name, quoteSqliteIdent(table))
+}
+
+func quoteSqliteIdent(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+func (sqliteIntrospector) columns(exec queryer, table string) ([]Column, error) {
+	type row struct {
+		Name    string         `db:"name"`
+		Type    string         `db:"type"`
+		NotNull int            `db:"notnull"`
+		Default sql.NullString `db:"dflt_value"`
+		PK      int            `db:"pk"`
+	}
+	rows, err := exec.Select(row{}, pragma("table_info", table))
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]Column, len(rows))
+	for i, r := range rows {
+		c := r.(*row)
+		cols[i] = Column{
+			Name:      c.Name,
+			Type:      c.Type,
+			Nullable:  c.NotNull == 0,
+			Default:   c.Default.String,
+			IsPrimary: c.PK > 0,
+		}
+	}
+	return cols, nil
+}
+
+func (s sqliteIntrospector) indexes(exec queryer, table string) ([]Index, error) {
+	type listRow struct {
+		Name   string `db:"name"`
+		Unique int    `db:"unique"`
+		Origin string `db:"origin"`
+	}
+	listRows, err := exec.Select(listRow{}, pragma("index_list", table))
+	if err != nil {
+		return nil, err
+	}
+
+	type infoRow struct {
+		Name string `db:"name"`
+	}
+
+	var idxs []Index
+	for _, r := range listRows {
+		l := r.(*listRow)
+		if l.Origin == "pk" {
+			continue
+		}
+		infoRows, err := exec.Select(infoRow{}, pragma("index_info", l.Name))
+		if err != nil {
+			return nil, err
+		}
+		cols := make([]string, len(infoRows))
+		for i, ir := range infoRows {
+			cols[i] = ir.(*infoRow).Name
+		}
+		idxs = append(idxs, Index{Name: l.Name, Columns: cols, Unique: l.Unique != 0})
+	}
+	return idxs, nil
+}
+
+func (sqliteIntrospector) foreignKeys(exec queryer, table string) ([]ForeignKey, error) {
+	type row struct {
+		Table string `db:"table"`
+		From  string `db:"from"`
+		To    string `db:"to"`
+		ID    int    `db:"id"`
+	}
+	rows, err := exec.Select(row{}, pragma("foreign_key_list", table))
+	if err != nil {
+		return nil, err
+	}
+
+	fks := make([]ForeignKey, len(rows))
+	for i, r := range rows {
+		fk := r.(*row)
+		fks[i] = ForeignKey{
+			Name:      fmt.Sprintf("%s_fk%d", table, fk.ID),
+			Column:    fk.From,
+			RefTable:  fk.Table,
+			RefColumn: fk.To,
+		}
+	}
+	return fks, nil
+}
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/mmrath/gorp"
+	"github.com/mmrath/gorp/migrate"
+)
+
+// ToMigrations renders each Change as a gorp/migrate Migration, so drift
+// detected by Diff can be applied (or reviewed) the same way a hand-written
+// migration would be. Each migration is named "schema-diff-<table>-<N>"
+// and is not reversible (Down is nil), since Diff only describes how to
+// make live match registered, not the other way around.
+func ToMigrations(changes []Change, dialect gorp.Dialect) []migrate.Migration {
+	migrations := make([]migrate.Migration, len(changes))
+	for i, c := range changes {
+		sql := changeSQL(c, dialect)
+		id := fmt.Sprintf("schema-diff-%s-%d", c.Table, i)
+		migrations[i] = migrate.FromSQL(id, sql, "")
+	}
+	return migrations
+}
+
+func changeSQL(c Change, dialect gorp.Dialect) string {
+	table := dialect.QuotedTableForQuery("", c.Table)
+	switch c.Kind {
+	case AddColumn:
+		return fmt.Sprintf("alter table %s add column %s %s", table, dialect.QuoteField(c.Column), c.Detail)
+	case DropColumn:
+		return fmt.Sprintf("alter table %s drop column %s", table, dialect.QuoteField(c.Column))
+	case DropTable:
+		return fmt.Sprintf("drop table %s", table)
+	case DropIndex:
+		return fmt.Sprintf("drop index %s", dialect.QuoteField(c.Index))
+	default:
+		// AddTable, AlterColumn and AddIndex need more context (full column
+		// list, a type-conversion strategy, or the index's columns) than a
+		// Change alone carries; callers
+		// reviewing Status/Diff output should write those by hand.
+		return fmt.Sprintf("-- %s: needs manual review (%s)", c.Kind, c)
+	}
+}
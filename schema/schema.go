@@ -0,0 +1,100 @@
+// Package schema introspects a connected gorp.DbMap's live database schema
+// and can dump it back out as dialect-specific DDL, or diff it against
+// another Schema so drift between the two can be detected (and, via
+// gorp/migrate, corrected). The package does not build a Schema from the
+// tables registered with AddTableWithName; the registered side of a Diff
+// is a Schema the caller assembles.
+package schema
+
+import "fmt"
+
+// Column describes one column of a table as it actually exists in the
+// database. Type is the dialect's own type name (e.g. "character varying",
+// "NVARCHAR"), not a gorp Go type, since it comes from introspection
+// rather than from a registered struct field.
+type Column struct {
+	Name      string
+	Type      string
+	Nullable  bool
+	Default   string
+	IsPrimary bool
+}
+
+// Index describes one index on a table, including the implicit index
+// backing a unique constraint.
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// ForeignKey describes one foreign key constraint.
+type ForeignKey struct {
+	Name      string
+	Column    string
+	RefTable  string
+	RefColumn string
+}
+
+// Table is one table's full structure.
+type Table struct {
+	Name        string
+	Columns     []Column
+	Indexes     []Index
+	ForeignKeys []ForeignKey
+}
+
+// Schema is a portable, dialect-independent description of a database as
+// plain Go structs. Dump
+// renders it as DDL for a specific dialect; Diff compares two of them.
+type Schema struct {
+	Tables []Table
+}
+
+// Table looks up a table by name, returning (Table{}, false) if it isn't
+// present.
+func (s *Schema) Table(name string) (Table, bool) {
+	for _, t := range s.Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// Column looks up a column by name within t, returning (Column{}, false)
+// if it isn't present.
+func (t *Table) Column(name string) (Column, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// introspector is implemented once per gorp Dialect and does the
+// information_schema / sqlite_master legwork for Introspect.
+type introspector interface {
+	tableNames(exec queryer) ([]string, error)
+	columns(exec queryer, table string) ([]Column, error)
+	indexes(exec queryer, table string) ([]Index, error)
+	foreignKeys(exec queryer, table string) ([]ForeignKey, error)
+}
+
+// queryer is the subset of gorp.SqlExecutor introspection needs: enough to
+// run ad hoc SELECTs against catalog tables, scanning rows into small
+// unregistered structs the way gorp.DbMap.Select already supports.
+type queryer interface {
+	Select(i interface{}, query string, args ...interface{}) ([]interface{}, error)
+}
+
+// unsupportedDialectError is returned by Introspect for a Dialect this
+// package has no introspector for.
+type unsupportedDialectError struct {
+	dialect interface{}
+}
+
+func (e unsupportedDialectError) Error() string {
+	return fmt.Sprintf("schema: no introspector for dialect %T", e.dialect)
+}
@@ -0,0 +1,12 @@
+package schema
+
+import "strings"
+
+// splitCSV splits a comma-joined column list (as produced by Postgres's
+// string_agg) back into its parts, ignoring an empty input.
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}